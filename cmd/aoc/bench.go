@@ -0,0 +1,118 @@
+package main
+
+import (
+	"aoc23/lib"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+// pctChange formats the change from base to cur as a percentage.
+func pctChange(base, cur int64) string {
+	if base == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f%%", float64(cur-base)*100/float64(base))
+}
+
+func benchCmd(args []string) error {
+	fs := newFlagSet("bench")
+	count := fs.Int("count", 5, "number of times to run each stage")
+	input := fs.String("input", "", "input file, or - for stdin (default dayNN/input.txt)")
+	save := fs.String("save", "", "write the results to this baseline file")
+	baseline := fs.String("baseline", "", "compare the results against this baseline file")
+	generate := fs.Int("generate", 0, "bench on this many lines of generated input instead (not all days support this)")
+	seed := fs.Int64("seed", 1, "random seed for --generate")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		positional = []string{"all"}
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected at most one day (or \"all\"), got %d", len(positional))
+	}
+	days, err := selectDays(positional[0])
+	if err != nil {
+		return err
+	}
+	if *input != "" && len(days) > 1 {
+		return fmt.Errorf("--input cannot be used with multiple days")
+	}
+	if *input != "" && *generate > 0 {
+		return fmt.Errorf("--input cannot be used with --generate")
+	}
+
+	var base map[lib.BenchKey]lib.BenchResult
+	if *baseline != "" {
+		if base, err = lib.LoadBenchResults(*baseline); err != nil {
+			return err
+		}
+	}
+
+	var results []lib.BenchResult
+	for _, d := range days {
+		var lines []string
+		if *generate > 0 {
+			if d.Generate == nil {
+				if len(days) > 1 {
+					continue
+				}
+				return fmt.Errorf("day %d cannot generate input", d.Number)
+			}
+			lines = d.Generate(*generate, *seed)
+		} else {
+			filename := *input
+			if filename == "" {
+				filename = dayDir(d) + "/input.txt"
+			}
+			if lines, err = readInput(filename); err != nil {
+				return err
+			}
+		}
+		res, err := d.Bench(lines, *count)
+		if err != nil {
+			return err
+		}
+		results = append(results, res...)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	header := "day\tstage\tns/op\tB/op\tallocs/op\t"
+	if base != nil {
+		header += "Δ ns/op\tΔ B/op\tΔ allocs/op\t"
+	}
+	fmt.Fprintln(tw, header)
+	var totalNs int64
+	for _, br := range results {
+		totalNs += br.NsPerOp
+		fmt.Fprintf(tw, "%02d\t%s\t%d\t%d\t%d\t", br.Day, br.Stage, br.NsPerOp, br.BytesPerOp, br.AllocsPerOp)
+		if base != nil {
+			if bbr, ok := base[br.BenchKey]; ok {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t",
+					pctChange(bbr.NsPerOp, br.NsPerOp),
+					pctChange(bbr.BytesPerOp, br.BytesPerOp),
+					pctChange(bbr.AllocsPerOp, br.AllocsPerOp))
+			} else {
+				fmt.Fprintf(tw, "new\t\t\t")
+			}
+		}
+		fmt.Fprintln(tw)
+	}
+	fmt.Fprintf(tw, "all\ttotal\t%d\t\t\t\n", totalNs)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if *save != "" {
+		fh, err := os.Create(*save)
+		if err != nil {
+			return err
+		}
+		defer fh.Close()
+		return lib.WriteBenchResults(fh, results)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package main
+
+// Each day registers itself with the lib solver registry on import.
+import (
+	_ "aoc23/day01"
+	_ "aoc23/day02"
+	_ "aoc23/day03"
+	_ "aoc23/day04"
+	_ "aoc23/day05"
+	_ "aoc23/day06"
+	_ "aoc23/day07"
+	_ "aoc23/day08"
+	_ "aoc23/day09"
+	_ "aoc23/day10"
+	_ "aoc23/day11"
+)
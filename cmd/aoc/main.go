@@ -0,0 +1,115 @@
+// Command aoc runs the registered Advent of Code solutions.
+package main
+
+import (
+	"aoc23/lib"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{"run", "run <day|all> [--part N] [--input FILE]", runCmd},
+	{"verify", "verify [day|all] [--samples]", verifyCmd},
+	{"bench", "bench [day|all] [--count N] [--input FILE | --generate N [--seed S]] [--save FILE] [--baseline FILE]", benchCmd},
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage:\n")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "\taoc %s\n", c.usage)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, c := range commands {
+		if c.name != os.Args[1] {
+			continue
+		}
+		if err := c.run(os.Args[2:]); errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		} else if err != nil {
+			fmt.Fprintf(os.Stderr, "aoc %s: %v\n", c.name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "Unknown command %q\n", os.Args[1])
+	usage()
+	os.Exit(2)
+}
+
+// Logging flags shared by every command.
+var (
+	verbose     bool
+	veryVerbose bool
+	traceAll    bool
+	scopeLevels string
+)
+
+// newFlagSet returns a FlagSet for a command, with the logging flags and every
+// day's own flags already registered.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.BoolVar(&verbose, "v", false, "log at info level")
+	fs.BoolVar(&veryVerbose, "vv", false, "log at debug level")
+	fs.BoolVar(&traceAll, "vvv", false, "log at trace level")
+	fs.StringVar(&scopeLevels, "log", "", "per-scope log levels, e.g. day07=trace,day08=info")
+	addDayFlags(fs)
+	return fs
+}
+
+// parseArgs parses fs from args, allowing positional arguments to appear
+// before, between or after flags, then applies the logging flags. It returns
+// the positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	switch {
+	case traceAll:
+		lib.SetLogLevel(lib.LevelTrace)
+	case veryVerbose:
+		lib.SetLogLevel(lib.LevelDebug)
+	case verbose:
+		lib.SetLogLevel(lib.LevelInfo)
+	}
+	if scopeLevels != "" {
+		for _, sl := range strings.Split(scopeLevels, ",") {
+			scope, level, ok := strings.Cut(sl, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid -log entry %q: want scope=level", sl)
+			}
+			ll, err := lib.ParseLogLevel(level)
+			if err != nil {
+				return nil, err
+			}
+			lib.SetScopeLogLevel(scope, ll)
+		}
+	}
+	return positional, nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"aoc23/lib"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+)
+
+// addDayFlags exposes every day's own flags on fs, prefixed with the day's
+// FlagSet name (e.g. -day08.naive).
+func addDayFlags(fs *flag.FlagSet) {
+	for _, d := range lib.Days() {
+		if d.Flags == nil {
+			continue
+		}
+		prefix := d.Flags.Name()
+		d.Flags.VisitAll(func(f *flag.Flag) {
+			fs.Var(f.Value, prefix+"."+f.Name, f.Usage)
+		})
+	}
+}
+
+// selectDays resolves a "<day>" or "all" argument to registered days.
+func selectDays(arg string) ([]*lib.Day, error) {
+	if arg == "all" {
+		return lib.Days(), nil
+	}
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid day %q", arg)
+	}
+	d, ok := lib.LookupDay(n)
+	if !ok {
+		return nil, fmt.Errorf("day %d is not solved", n)
+	}
+	return []*lib.Day{d}, nil
+}
+
+// dayDir returns the directory holding a day's inputs.
+func dayDir(d *lib.Day) string {
+	return fmt.Sprintf("day%02d", d.Number)
+}
+
+// readInput loads the named input file, with "-" meaning stdin.
+func readInput(filename string) ([]string, error) {
+	if filename == "-" {
+		return lib.GetInputAll(os.Stdin)
+	}
+	return lib.GetInputFileAll(filename)
+}
+
+// openInput opens the named input file for streaming, with "-" meaning stdin.
+func openInput(filename string) (lib.TextIterator, error) {
+	if filename == "-" {
+		return lib.GetInputIterator(os.Stdin)
+	}
+	return lib.GetInputFileIterator(filename)
+}
+
+// streamDay solves a day in one pass over its input, without loading it. An
+// interrupt stops the pass.
+func streamDay(d *lib.Day, filename string, parts []int) ([]lib.Result, error) {
+	it, err := openInput(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return d.RunStream(ctx, filename, it, parts...)
+}
+
+func runCmd(args []string) error {
+	fs := newFlagSet("run")
+	part := fs.Int("part", 0, "only solve this part (0 for both)")
+	input := fs.String("input", "", "input file, or - for stdin (default dayNN/input.txt)")
+	format := fs.String("format", "text", "output format: "+strings.Join(lib.ResultFormats, ", "))
+	stream := fs.Bool("stream", false, "solve in a single pass without loading the input (not all days support this)")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected exactly one day (or \"all\"), got %d", len(positional))
+	}
+	if *part < 0 || *part > 2 {
+		return fmt.Errorf("invalid part %d", *part)
+	}
+	days, err := selectDays(positional[0])
+	if err != nil {
+		return err
+	}
+	if *input != "" && len(days) > 1 {
+		return fmt.Errorf("--input cannot be used with multiple days")
+	}
+	out, err := lib.NewResultWriter(os.Stdout, *format)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range days {
+		filename := *input
+		if filename == "" {
+			filename = dayDir(d) + "/input.txt"
+		}
+		var parts []int
+		if *part != 0 {
+			parts = []int{*part}
+		}
+
+		var results []lib.Result
+		if *stream {
+			if d.Stream == nil && len(days) > 1 {
+				continue
+			}
+			results, err = streamDay(d, filename, parts)
+		} else {
+			var lines []string
+			if lines, err = readInput(filename); err == nil {
+				results, err = d.Run(filename, lines, parts...)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		for _, r := range results {
+			if err := out.Write(r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"aoc23/lib"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+)
+
+type verifyStatus int
+
+const (
+	verifyPass verifyStatus = iota
+	verifyFail
+	verifyMissing
+)
+
+func (vs verifyStatus) String() string {
+	switch vs {
+	case verifyPass:
+		return "PASS"
+	case verifyFail:
+		return "FAIL"
+	case verifyMissing:
+		return "MISSING"
+	default:
+		return "invalid"
+	}
+}
+
+func verifyCmd(args []string) error {
+	flags := newFlagSet("verify")
+	samplesOnly := flags.Bool("samples", false, "only verify the sample inputs")
+
+	positional, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		positional = []string{"all"}
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected at most one day (or \"all\"), got %d", len(positional))
+	}
+	days, err := selectDays(positional[0])
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[verifyStatus]int)
+	for _, d := range days {
+		answers, err := lib.LoadAnswers(filepath.Join(dayDir(d), "answers.txt"))
+		if errors.Is(err, fs.ErrNotExist) {
+			answers = make(lib.Answers)
+		} else if err != nil {
+			return err
+		}
+
+		inputs, err := lib.InputFiles(dayDir(d), *samplesOnly)
+		if err != nil {
+			return err
+		}
+		for _, filename := range inputs {
+			name := filepath.Base(filename)
+			lines, err := lib.GetInputFileAll(filename)
+			if err != nil {
+				return err
+			}
+
+			var got [2]string
+			got[0], got[1], err = d.Solve(lines)
+			err = lib.WithSource(err, filename)
+			for i := range got {
+				key := lib.AnswerKey{Input: name, Part: i + 1}
+				want, ok := answers[key]
+
+				status := verifyPass
+				detail := ""
+				switch {
+				case err != nil:
+					status = verifyFail
+					detail = fmt.Sprintf(" (error: %v)", err)
+				case !ok && got[i] == "":
+					continue // part does not apply to this input
+				case !ok:
+					status = verifyMissing
+					detail = fmt.Sprintf(" (got %s)", got[i])
+				case got[i] != want:
+					status = verifyFail
+					detail = fmt.Sprintf(" (got %q, want %q)", got[i], want)
+				}
+				counts[status]++
+				fmt.Printf("day %02d %-20s part %d: %s%s\n", d.Number, name, i+1, status, detail)
+			}
+		}
+	}
+
+	fmt.Printf("%d passed, %d failed, %d missing\n", counts[verifyPass], counts[verifyFail], counts[verifyMissing])
+	if counts[verifyFail] > 0 {
+		return fmt.Errorf("%d answers failed verification", counts[verifyFail])
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package day01
+
+import (
+	"aoc23/lib"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var (
+	logger = lib.NewLogger("day01")
+
+	flags     = flag.NewFlagSet("day01", flag.ContinueOnError)
+	words     = flags.String("words", "en", "spelled-out numbers for part 2: any of "+strings.Join(lib.Keys(lib.Vocabularies), ", ")+", comma separated")
+	wordsFile = flags.String("wordsfile", "", "also read \"<word> <number>\" lines from this file for part 2")
+	spans     = flags.Bool("spans", false, "write the numbers each part matches on each line to stderr")
+)
+
+// newMatcher returns a matcher for digits, and for spelled-out numbers too
+// if useWords is set.
+func newMatcher(useWords bool) (*lib.WordMatcher, error) {
+	vocabs := []lib.Vocabulary{lib.DigitVocabulary}
+	if useWords {
+		for _, name := range strings.Split(*words, ",") {
+			v, ok := lib.Vocabularies[strings.TrimSpace(name)]
+			if !ok {
+				return nil, fmt.Errorf("unknown vocabulary %q", name)
+			}
+			vocabs = append(vocabs, v)
+		}
+		if *wordsFile != "" {
+			fh, err := os.Open(*wordsFile)
+			if err != nil {
+				return nil, err
+			}
+			v, err := lib.ReadVocabulary(fh)
+			if err != nil {
+				return nil, lib.WithSource(err, *wordsFile)
+			}
+			vocabs = append(vocabs, v)
+		}
+	}
+	return lib.NewWordMatcher(lib.Merge(vocabs...))
+}
+
+// calibrationValue combines the first and last number found on a line by m.
+// It returns false if the line has none.
+func calibrationValue(ln int, line string, m *lib.WordMatcher) (int, bool) {
+	logger.Debugf("---- Line (%2d): %q", ln, line)
+	nums := m.FindAll(line)
+	logger.Tracef("Got %d numbers: %v", len(nums), nums)
+	if len(nums) == 0 {
+		logger.Infof("No digits on line %d", ln)
+		return 0, false
+	}
+	num := nums[0].Value*10 + nums[len(nums)-1].Value
+	logger.Tracef("Got number %d", num)
+	return num, true
+}
+
+// calibrationSum adds up the calibration value of each line.
+func calibrationSum(lines []string, useWords bool) (int, bool, error) {
+	m, err := newMatcher(useWords)
+	if err != nil {
+		return 0, false, err
+	}
+	sum := 0
+	for ln, line := range lines {
+		num, ok := calibrationValue(ln+1, line, m)
+		if !ok {
+			return 0, false, nil
+		}
+		sum += num
+	}
+
+	logger.Infof("Sum: %d", sum)
+	return sum, true, nil
+}
+
+// report writes the numbers each part matches on each line, if -spans is set.
+func report(lines []string) error {
+	if !*spans {
+		return nil
+	}
+	for part, useWords := range []bool{false, true} {
+		m, err := newMatcher(useWords)
+		if err != nil {
+			return err
+		}
+		writeSpans(os.Stderr, part+1, lines, m)
+	}
+	return nil
+}
+
+// writeSpans lists the numbers m finds on each line, and the calibration
+// value they make.
+func writeSpans(w io.Writer, part int, lines []string, m *lib.WordMatcher) {
+	for ln, line := range lines {
+		nums := m.FindAll(line)
+		if len(nums) == 0 {
+			fmt.Fprintf(w, "part %d line %d: no numbers\n", part, ln+1)
+			continue
+		}
+		num := nums[0].Value*10 + nums[len(nums)-1].Value
+		fmt.Fprintf(w, "part %d line %d: %d from %v\n", part, ln+1, num, nums)
+	}
+}
+
+func init() {
+	lib.Register(lib.Day{
+		Number: 1,
+		Parse:  lib.ParseFunc(parse),
+		Part1:  lib.PartFunc(part1),
+		Part2:  lib.PartFunc(part2),
+		Report: lib.ReportFunc(report),
+		Stream: stream,
+		Flags:  flags,
+	})
+}
+
+func parse(lines []string) ([]string, error) {
+	return lines, nil
+}
+
+func part1(lines []string) (string, error) {
+	sum, ok, err := calibrationSum(lines, false)
+	if !ok {
+		return "", err
+	}
+	return strconv.Itoa(sum), nil
+}
+
+func part2(lines []string) (string, error) {
+	sum, ok, err := calibrationSum(lines, true)
+	if !ok {
+		return "", err
+	}
+	return strconv.Itoa(sum), nil
+}
+
+// stream computes both sums in one pass, a line at a time.
+func stream(lines *lib.LineIterator) (string, string, error) {
+	var ms [2]*lib.WordMatcher
+	for i, useWords := range []bool{false, true} {
+		var err error
+		if ms[i], err = newMatcher(useWords); err != nil {
+			return "", "", err
+		}
+	}
+
+	sums := [2]int{}
+	ok := [2]bool{true, true}
+	for lines.Next() {
+		l := lines.Line()
+		for i, m := range ms {
+			if !ok[i] {
+				continue
+			}
+			var num int
+			num, ok[i] = calibrationValue(l.Num, l.Text, m)
+			sums[i] += num
+		}
+	}
+	if err := lines.Err(); err != nil {
+		return "", "", err
+	}
+
+	var res [2]string
+	for i := range res {
+		if ok[i] {
+			logger.Infof("Sum: %d", sums[i])
+			res[i] = strconv.Itoa(sums[i])
+		}
+	}
+	return res[0], res[1], nil
+}
@@ -0,0 +1,14 @@
+package day01
+
+import (
+	"aoc23/lib/daytest"
+	"testing"
+)
+
+func TestAnswers(t *testing.T) {
+	daytest.Answers(t, 1)
+}
+
+func BenchmarkParse(b *testing.B) { daytest.Bench(b, 1, "parse") }
+func BenchmarkPart1(b *testing.B) { daytest.Bench(b, 1, "part1") }
+func BenchmarkPart2(b *testing.B) { daytest.Bench(b, 1, "part2") }
@@ -0,0 +1,376 @@
+package day02
+
+import (
+	"aoc23/lib"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var (
+	logger = lib.NewLogger("day02")
+
+	flags    = flag.NewFlagSet("day02", flag.ContinueOnError)
+	strict   = flags.Bool("strict", false, "reject games drawing colours that are not in the bag")
+	minimal  = flags.Bool("minimal", false, "write the minimal bag for each game to stderr")
+	binding  = flags.Bool("binding", false, "write the colour limits that make each game impossible to stderr")
+	subset   = flags.String("subset", "", "write the smallest bag making these games possible to stderr: comma separated game IDs")
+	bagLimit = cubes{"red": 12, "green": 13, "blue": 14}
+)
+
+func init() {
+	flags.Var(&bagLimit, "bag", "the part 1 bag, as \"<count> <colour>, ...\"")
+	flags.Func("bagfile", "read the part 1 bag from this file, one or more \"<count> <colour>\" per line, replacing -bag", func(filename string) error {
+		fh, err := os.Open(filename)
+		if err != nil {
+			return err
+		}
+		bag, err := readBag(fh)
+		if err != nil {
+			return lib.WithSource(err, filename)
+		}
+		bagLimit = bag
+		return nil
+	})
+}
+
+// cubes counts cubes by colour, for a round drawn from the bag or for the
+// bag itself. Colours not in the map have none.
+type cubes map[string]int
+
+// String formats c as a round, e.g. "14 blue, 12 red", colours in order.
+func (c cubes) String() string {
+	var parts []string
+	for _, colour := range lib.Keys(c) {
+		parts = append(parts, fmt.Sprintf("%d %s", c[colour], colour))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Set replaces c with the cubes of a round, as the -bag flag.
+func (c *cubes) Set(s string) error {
+	res, err := parseCubes(lib.Pos{}, s)
+	if err != nil {
+		return err
+	}
+	*c = res
+	return nil
+}
+
+// add adds the cubes of a round read at p to c. Colours are not case
+// sensitive.
+func (c cubes) add(p lib.Pos, round []cubeLine) error {
+	for _, cube := range round {
+		if cube.Count < 0 {
+			return p.Errorf(strconv.Itoa(cube.Count), "negative count of %s cubes", cube.Colour)
+		}
+		c[strings.ToLower(cube.Colour)] += cube.Count
+	}
+	return nil
+}
+
+// parseCubes reads a comma-separated list of "<count> <colour>".
+func parseCubes(p lib.Pos, s string) (cubes, error) {
+	var rl roundLine
+	if err := lib.UnmarshalAt(p, s, &rl); err != nil {
+		return nil, err
+	}
+	res := make(cubes)
+	if err := res.add(p, rl.Cubes); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// readBag reads a bag file of rounds, adding them up. Blank lines and lines
+// starting with '#' are skipped.
+func readBag(r io.ReadCloser) (cubes, error) {
+	lines, err := lib.GetInputAll(r)
+	if err != nil {
+		return nil, err
+	}
+	res := make(cubes)
+	for ln, line := range lines {
+		if line = strings.TrimSpace(line); line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		c, err := parseCubes(lib.Pos{Line: ln + 1}, line)
+		if err != nil {
+			return nil, err
+		}
+		for colour, n := range c {
+			res[colour] += n
+		}
+	}
+	return res, nil
+}
+
+// covers reports whether c holds at least the cubes in need.
+func (c cubes) covers(need cubes) bool {
+	for colour, n := range need {
+		if n > c[colour] {
+			return false
+		}
+	}
+	return true
+}
+
+// short returns the colours where c does not cover need, and by how many.
+func (c cubes) short(need cubes) cubes {
+	res := make(cubes)
+	for colour, n := range need {
+		if n > c[colour] {
+			res[colour] = n - c[colour]
+		}
+	}
+	return res
+}
+
+// union returns the smallest bag covering both c and o.
+func (c cubes) union(o cubes) cubes {
+	res := make(cubes)
+	for _, m := range []cubes{c, o} {
+		for colour, n := range m {
+			res[colour] = lib.Max(res[colour], n)
+		}
+	}
+	return res
+}
+
+// power multiplies the counts of the given colours.
+func (c cubes) power(colours []string) int {
+	res := 1
+	for _, colour := range colours {
+		res *= c[colour]
+	}
+	return res
+}
+
+type gameInfo struct {
+	gameId int
+	rounds []cubes
+}
+
+// NumNeeded returns the minimal bag for the game: the most cubes of each
+// colour drawn in any round.
+func (gi gameInfo) NumNeeded() cubes {
+	res := make(cubes)
+	for _, round := range gi.rounds {
+		res = res.union(round)
+	}
+	return res
+}
+
+func (gi gameInfo) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Game %3d (%d rounds) = [", gi.gameId, len(gi.rounds))
+	for i, r := range gi.rounds {
+		fmt.Fprintf(&sb, "(Round %d: %v)", i, r)
+		if i < len(gi.rounds)-1 {
+			sb.WriteString(", ")
+		}
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
+
+// gameLine is the layout of a game record.
+type gameLine struct {
+	_      struct{} `line:"Game {id}: {rounds;sep=\";\"}"`
+	ID     int
+	Rounds []roundLine
+}
+
+type roundLine struct {
+	_     struct{} `line:"{cubes;sep=\",\"}"`
+	Cubes []cubeLine
+}
+
+type cubeLine struct {
+	_      struct{} `line:"{count} {colour}"`
+	Count  int
+	Colour string
+}
+
+// parseGame reads a "Game <id>: <round>; <round>..." line, where each round
+// is a comma-separated list of "<count> <colour>". In strict mode, colours
+// not in the bag are an error.
+func parseGame(linen int, line string) (*gameInfo, error) {
+	var gl gameLine
+	pos := lib.Pos{Line: linen}
+	if err := lib.UnmarshalAt(pos, line, &gl); err != nil {
+		return nil, err
+	}
+
+	info := &gameInfo{
+		gameId: gl.ID,
+		rounds: make([]cubes, len(gl.Rounds)),
+	}
+	for roundn, round := range gl.Rounds {
+		info.rounds[roundn] = make(cubes)
+		if err := info.rounds[roundn].add(pos, round.Cubes); err != nil {
+			return nil, err
+		}
+		for colour := range info.rounds[roundn] {
+			if _, ok := bagLimit[colour]; ok {
+				continue
+			}
+			if *strict {
+				return nil, pos.Errorf(colour, "game %d round %d: unknown colour (the bag holds %s)", gl.ID, roundn+1, strings.Join(lib.Keys(bagLimit), ", "))
+			}
+			logger.Infof("Game %d round %d: %q is not in the bag", gl.ID, roundn+1, colour)
+		}
+	}
+
+	return info, nil
+}
+
+func init() {
+	lib.Register(lib.Day{
+		Number: 2,
+		Parse:  lib.ParseFunc(parse),
+		Part1:  lib.PartFunc(part1),
+		Part2:  lib.PartFunc(part2),
+		Report: lib.ReportFunc(report),
+		Flags:  flags,
+	})
+}
+
+func parse(lines []string) ([]*gameInfo, error) {
+	logger.Debugf("Bag: %v", bagLimit)
+
+	var games []*gameInfo
+	for ln, line := range lines {
+		logger.Debugf("----- Line(%2d) %q", ln+1, line)
+		info, err := parseGame(ln+1, line)
+		if err != nil {
+			return nil, err
+		}
+		logger.Debugf("%+v", info)
+		games = append(games, info)
+	}
+	return games, nil
+}
+
+// report answers the -minimal, -binding and -subset queries.
+func report(games []*gameInfo) error {
+	if *minimal {
+		writeMinimal(os.Stderr, games)
+	}
+	if *binding {
+		writeBinding(os.Stderr, games, bagLimit)
+	}
+	if *subset != "" {
+		return writeSubset(os.Stderr, games, *subset)
+	}
+	return nil
+}
+
+// drawnColours returns every colour drawn in any of the games, sorted.
+func drawnColours(games []*gameInfo) []string {
+	all := make(cubes)
+	for _, g := range games {
+		all = all.union(g.NumNeeded())
+	}
+	return lib.Keys(all)
+}
+
+// writeMinimal lists the minimal bag for each game, and its power.
+func writeMinimal(w io.Writer, games []*gameInfo) {
+	colours := drawnColours(games)
+	for _, g := range games {
+		need := g.NumNeeded()
+		fmt.Fprintf(w, "Game %d: %v (power %d)\n", g.gameId, need, need.power(colours))
+	}
+}
+
+// writeBinding lists, for each game that bag makes impossible, the colours it
+// is short of. Where only one colour is short, raising that limit alone makes
+// the game possible.
+func writeBinding(w io.Writer, games []*gameInfo, bag cubes) {
+	for _, g := range games {
+		need := g.NumNeeded()
+		short := bag.short(need)
+		switch len(short) {
+		case 0:
+			continue
+		case 1:
+			colour := lib.Keys(short)[0]
+			fmt.Fprintf(w, "Game %d: bound by %s, needs %d not %d\n", g.gameId, colour, need[colour], bag[colour])
+		default:
+			fmt.Fprintf(w, "Game %d: no single limit binds, short of %v\n", g.gameId, short)
+		}
+	}
+}
+
+// writeSubset gives the smallest bag making all the games listed in ids
+// possible.
+func writeSubset(w io.Writer, games []*gameInfo, ids string) error {
+	byID := make(map[int]*gameInfo)
+	for _, g := range games {
+		byID[g.gameId] = g
+	}
+	bag := make(cubes)
+	for _, f := range strings.Split(ids, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil {
+			return fmt.Errorf("bad game ID %q in -subset", f)
+		}
+		g, ok := byID[id]
+		if !ok {
+			return fmt.Errorf("no game %d for -subset", id)
+		}
+		bag = bag.union(g.NumNeeded())
+	}
+	fmt.Fprintf(w, "Games %s: smallest bag %v\n", ids, bag)
+	return nil
+}
+
+// part1 sums the IDs of the games possible with the given bag.
+func part1(games []*gameInfo) (string, error) {
+	sum := 0
+	var possibleGames []*gameInfo
+
+	logger.Debugf("Finding possible games with bag %v", bagLimit)
+	for _, g := range games {
+		need := g.NumNeeded()
+		if bagLimit.covers(need) {
+			possibleGames = append(possibleGames, g)
+			sum += g.gameId
+			logger.Debugf("\tGame %3d:     POSSIBLE. Needs %v", g.gameId, need)
+		} else {
+			logger.Debugf("\tGame %3d: NOT POSSIBLE. Needs %v", g.gameId, need)
+		}
+	}
+
+	logger.Infof("%d possible games", len(possibleGames))
+	logger.Infof("Sum of possible games: %d", sum)
+	return strconv.Itoa(sum), nil
+}
+
+// part2 sums the power of the minimal bag for each game, over every colour
+// drawn in any game, so that a game never drawing some colour has no power.
+// The part 1 bag plays no part.
+func part2(games []*gameInfo) (string, error) {
+	all := make(cubes)
+	needs := make([]cubes, len(games))
+	for i, g := range games {
+		needs[i] = g.NumNeeded()
+		all = all.union(needs[i])
+	}
+
+	colours := lib.Keys(all)
+	powerSum := 0
+	for i, g := range games {
+		power := needs[i].power(colours)
+		powerSum += power
+		logger.Debugf("\tGame %3d: Power = %d", g.gameId, power)
+	}
+
+	logger.Infof("Needs for all to be possible: %v", all)
+	logger.Infof("Sum of all powers: %d", powerSum)
+	return strconv.Itoa(powerSum), nil
+}
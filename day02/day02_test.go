@@ -0,0 +1,14 @@
+package day02
+
+import (
+	"aoc23/lib/daytest"
+	"testing"
+)
+
+func TestAnswers(t *testing.T) {
+	daytest.Answers(t, 2)
+}
+
+func BenchmarkParse(b *testing.B) { daytest.Bench(b, 2, "parse") }
+func BenchmarkPart1(b *testing.B) { daytest.Bench(b, 2, "part1") }
+func BenchmarkPart2(b *testing.B) { daytest.Bench(b, 2, "part2") }
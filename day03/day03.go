@@ -0,0 +1,325 @@
+package day03
+
+import (
+	"aoc23/lib"
+	"aoc23/lib/grid"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	logger = lib.NewLogger("day03")
+
+	flags    = flag.NewFlagSet("day03", flag.ContinueOnError)
+	blank    = flags.String("blank", ".", "characters that are neither numbers nor symbols, besides spaces")
+	doReport = flags.Bool("report", false, "write the numbers touching several symbols, and those touching none, to stderr")
+
+	gearRule = newRule("*=2:product")
+	queries  []*rule
+)
+
+func init() {
+	const usage = ": <symbols>[=<n>]:<aggregate>, e.g. \"*=2:product\" or \"any:sum\"; aggregates are " +
+		"count, max, min, product and sum"
+	flags.Var(gearRule, "gear", "the part 2 rule"+usage)
+	flags.Func("query", "write the total of a rule to stderr, may be repeated"+usage, func(spec string) error {
+		r := &rule{}
+		if err := r.Set(spec); err != nil {
+			return err
+		}
+		queries = append(queries, r)
+		return nil
+	})
+}
+
+// cellID says what covers a cell of the schematic: a number or a symbol, by
+// its index in the schematic, or nothing if zero.
+type cellID int32
+
+func numberID(i int) cellID { return cellID(i + 1) }
+func symbolID(i int) cellID { return cellID(-i - 1) }
+
+// number returns the index of the number covering the cell, if any.
+func (c cellID) number() (int, bool) { return int(c) - 1, c > 0 }
+
+type symbol struct {
+	name    rune
+	pos     grid.Point
+	numbers []int // adjacent numbers
+}
+
+type partNumber struct {
+	pos     grid.Point // position of the first digit
+	digits  int
+	value   int
+	symbols []int // adjacent symbols
+}
+
+// schematic indexes the numbers and symbols by the cells they cover, and
+// links each to the others it touches.
+type schematic struct {
+	cells   *grid.Grid[cellID]
+	symbols []*symbol
+	numbers []*partNumber
+}
+
+// at returns what covers p.
+func (s *schematic) at(p grid.Point) cellID {
+	return s.cells.At(p)
+}
+
+func isSymbol(r rune) bool {
+	return !lib.IsNum(r) && !unicode.IsSpace(r) && !strings.ContainsRune(*blank, r)
+}
+
+// aggregates combine the numbers around a symbol.
+var aggregates = map[string]func(vals []int) int{
+	"count": func(vals []int) int { return len(vals) },
+	"max": func(vals []int) int {
+		res := vals[0]
+		for _, v := range vals[1:] {
+			res = lib.Max(res, v)
+		}
+		return res
+	},
+	"min": func(vals []int) int {
+		res := vals[0]
+		for _, v := range vals[1:] {
+			res = lib.Min(res, v)
+		}
+		return res
+	},
+	"product": func(vals []int) int {
+		res := 1
+		for _, v := range vals {
+			res *= v
+		}
+		return res
+	},
+	"sum": func(vals []int) int {
+		res := 0
+		for _, v := range vals {
+			res += v
+		}
+		return res
+	},
+}
+
+// rule picks out symbols by name and by how many numbers they touch, and
+// combines the numbers around each. It is set from a spec
+// "<symbols>[=<n>]:<aggregate>": "*=2:product" for gears touching exactly two
+// numbers, or "any:sum" for every symbol touching at least one.
+type rule struct {
+	spec    string
+	symbols string // "" for any
+	count   int    // 0 for any
+	agg     func(vals []int) int
+}
+
+func newRule(spec string) *rule {
+	r := &rule{}
+	if err := r.Set(spec); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func (r *rule) String() string {
+	return r.spec
+}
+
+func (r *rule) Set(spec string) error {
+	match, agg, ok := strings.Cut(spec, ":")
+	if !ok {
+		return fmt.Errorf("rule %q: missing \":<aggregate>\"", spec)
+	}
+	res := rule{spec: spec, agg: aggregates[agg]}
+	if res.agg == nil {
+		return fmt.Errorf("rule %q: unknown aggregate %q, want one of %s", spec, agg, strings.Join(lib.Keys(aggregates), ", "))
+	}
+	symbols, count, ok := strings.Cut(match, "=")
+	if ok {
+		n, err := strconv.Atoi(count)
+		if err != nil || n < 1 {
+			return fmt.Errorf("rule %q: bad count %q", spec, count)
+		}
+		res.count = n
+	}
+	switch symbols {
+	case "":
+		return fmt.Errorf("rule %q: no symbols", spec)
+	case "any":
+	default:
+		res.symbols = symbols
+	}
+	*r = res
+	return nil
+}
+
+// matches reports whether the rule applies to sym.
+func (r *rule) matches(sym *symbol) bool {
+	if r.symbols != "" && !strings.ContainsRune(r.symbols, sym.name) {
+		return false
+	}
+	if r.count == 0 {
+		return len(sym.numbers) > 0
+	}
+	return len(sym.numbers) == r.count
+}
+
+// total applies the rule to each symbol it matches, adding up the results.
+// It also returns how many symbols matched.
+func (r *rule) total(s *schematic) (sum, matched int) {
+	for _, sym := range s.symbols {
+		if !r.matches(sym) {
+			continue
+		}
+		vals := make([]int, len(sym.numbers))
+		for i, n := range sym.numbers {
+			vals[i] = s.numbers[n].value
+		}
+		v := r.agg(vals)
+		logger.Debugf("%s: %c at (x,y)=%v, numbers %v: %d", r.spec, sym.name, sym.pos, vals, v)
+		sum += v
+		matched++
+	}
+	return sum, matched
+}
+
+func init() {
+	lib.Register(lib.Day{
+		Number: 3,
+		Parse:  lib.ParseFunc(parse),
+		Part1:  lib.PartFunc(part1),
+		Part2:  lib.PartFunc(part2),
+		Report: lib.ReportFunc(report),
+		Flags:  flags,
+	})
+}
+
+// parse loads the schematic and links each symbol to its adjacent numbers.
+func parse(lines []string) (*schematic, error) {
+	runes, err := grid.Runes(lines)
+	if err != nil {
+		return nil, err
+	}
+	s := &schematic{cells: grid.New[cellID](runes.Width(), runes.Height())}
+
+	// Iteration 1: Extract the numbers, noting which number covers each
+	// cell.
+	for ln, line := range lines {
+		toks, err := lib.Digits.Scan(lib.Pos{Line: ln + 1}, line)
+		if err != nil {
+			return nil, err
+		}
+		for _, tok := range toks {
+			pnum := &partNumber{
+				pos:    grid.Point{X: tok.Runes.Start, Y: ln},
+				digits: tok.Runes.Len(),
+				value:  tok.Int,
+			}
+			for i := 0; i < pnum.digits; i++ {
+				s.cells.Set(grid.Point{X: pnum.pos.X + i, Y: ln}, numberID(len(s.numbers)))
+			}
+			s.numbers = append(s.numbers, pnum)
+		}
+	}
+	logger.Infof("Loaded %d part numbers", len(s.numbers))
+
+	// Iteration 2: look for symbols.
+	for _, pos := range runes.FindAll(isSymbol) {
+		name := runes.At(pos)
+		logger.Tracef("Symbol %c at %v", name, pos)
+		s.cells.Set(pos, symbolID(len(s.symbols)))
+		s.symbols = append(s.symbols, &symbol{name: name, pos: pos})
+	}
+	logger.Infof("Loaded %d symbols", len(s.symbols))
+
+	// Link symbols and the numbers around them.
+	for i, sym := range s.symbols {
+		logger.Debugf("Symbol %c at (x,y)=%v", sym.name, sym.pos)
+		for _, pos := range s.cells.Neighbors8(sym.pos) {
+			n, ok := s.at(pos).number()
+			if !ok {
+				continue
+			}
+			alreadyUsed := false
+			for _, seen := range sym.numbers {
+				alreadyUsed = alreadyUsed || seen == n
+			}
+			if alreadyUsed {
+				continue // touches the symbol with more than one digit
+			}
+			pnum := s.numbers[n]
+			logger.Debugf("\tTouches part number %d at (x,y)=%v", pnum.value, pnum.pos)
+			sym.numbers = append(sym.numbers, n)
+			pnum.symbols = append(pnum.symbols, i)
+		}
+	}
+
+	return s, nil
+}
+
+// report writes the -report and -query output.
+func report(s *schematic) error {
+	if *doReport {
+		writeReport(os.Stderr, s)
+	}
+	for _, r := range queries {
+		sum, matched := r.total(s)
+		fmt.Fprintf(os.Stderr, "%s: %d from %d symbols\n", r.spec, sum, matched)
+	}
+	return nil
+}
+
+// writeReport lists the numbers touching more than one symbol, and the
+// orphans touching none.
+func writeReport(w io.Writer, s *schematic) {
+	var orphans []string
+	for _, pnum := range s.numbers {
+		switch len(pnum.symbols) {
+		case 0:
+			orphans = append(orphans, fmt.Sprintf("%d at %v", pnum.value, pnum.pos))
+		case 1:
+		default:
+			var syms []string
+			for _, i := range pnum.symbols {
+				syms = append(syms, fmt.Sprintf("%c at %v", s.symbols[i].name, s.symbols[i].pos))
+			}
+			fmt.Fprintf(w, "%d at %v touches %d symbols: %s\n", pnum.value, pnum.pos, len(syms), strings.Join(syms, ", "))
+		}
+	}
+	if len(orphans) == 0 {
+		fmt.Fprintln(w, "No orphans")
+		return
+	}
+	fmt.Fprintf(w, "%d orphans: %s\n", len(orphans), strings.Join(orphans, ", "))
+}
+
+// part1 sums the numbers adjacent to at least one symbol.
+func part1(s *schematic) (string, error) {
+	orphaned := 0
+	sum := 0
+	for _, pnum := range s.numbers {
+		if len(pnum.symbols) == 0 {
+			orphaned++
+		} else {
+			sum += pnum.value
+		}
+	}
+	logger.Infof("Orphaned %d part numbers", orphaned)
+	logger.Infof("Sum of non-orphaned part numbers: %d", sum)
+	return strconv.Itoa(sum), nil
+}
+
+// part2 sums the gear ratios, or whatever -gear asks for.
+func part2(s *schematic) (string, error) {
+	ratioSum, gears := gearRule.total(s)
+	logger.Infof("Sum of ratios over %d gears: %d", gears, ratioSum)
+	return strconv.Itoa(ratioSum), nil
+}
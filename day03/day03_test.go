@@ -0,0 +1,14 @@
+package day03
+
+import (
+	"aoc23/lib/daytest"
+	"testing"
+)
+
+func TestAnswers(t *testing.T) {
+	daytest.Answers(t, 3)
+}
+
+func BenchmarkParse(b *testing.B) { daytest.Bench(b, 3, "parse") }
+func BenchmarkPart1(b *testing.B) { daytest.Bench(b, 3, "part1") }
+func BenchmarkPart2(b *testing.B) { daytest.Bench(b, 3, "part2") }
@@ -0,0 +1,289 @@
+package day04
+
+import (
+	"aoc23/lib"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var (
+	logger = lib.NewLogger("day04")
+
+	flags   = flag.NewFlagSet("day04", flag.ContinueOnError)
+	explain = &cardSet{}
+)
+
+func init() {
+	flags.Var(explain, "explain", "write which earlier cards won the copies of these cards to stderr: comma separated card IDs, or all")
+}
+
+// cardSet picks out cards by ID, for -explain.
+type cardSet struct {
+	spec string
+	all  bool
+	ids  map[int]bool
+}
+
+func (cs *cardSet) String() string {
+	return cs.spec
+}
+
+func (cs *cardSet) Set(spec string) error {
+	res := cardSet{spec: spec, ids: make(map[int]bool)}
+	if spec == "all" {
+		res.all = true
+	} else {
+		for _, f := range strings.Split(spec, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(f))
+			if err != nil {
+				return fmt.Errorf("bad card ID %q", f)
+			}
+			res.ids[id] = true
+		}
+	}
+	*cs = res
+	return nil
+}
+
+func (cs *cardSet) empty() bool {
+	return !cs.all && len(cs.ids) == 0
+}
+
+func (cs *cardSet) has(id int) bool {
+	return cs.all || cs.ids[id]
+}
+
+// number is a count that is held in an int until it outgrows one, and in a
+// big.Int from then on. Counts are never negative.
+type number struct {
+	n   int
+	big *big.Int // the value, if non-nil, and n is unused
+}
+
+// pow2 returns 2**e.
+func pow2(e int) number {
+	if e < strconv.IntSize-1 {
+		return number{n: 1 << e}
+	}
+	return number{big: new(big.Int).Lsh(big.NewInt(1), uint(e))}
+}
+
+func (a number) isBig() bool {
+	return a.big != nil
+}
+
+func (a number) toBig() *big.Int {
+	if a.big != nil {
+		return a.big
+	}
+	return big.NewInt(int64(a.n))
+}
+
+// add returns a+b, switching to a big.Int if the sum does not fit an int.
+func (a number) add(b number) number {
+	if !a.isBig() && !b.isBig() {
+		if sum := a.n + b.n; sum >= a.n {
+			return number{n: sum}
+		}
+	}
+	return number{big: new(big.Int).Add(a.toBig(), b.toBig())}
+}
+
+func (a number) String() string {
+	if a.big != nil {
+		return a.big.String()
+	}
+	return strconv.Itoa(a.n)
+}
+
+type card struct {
+	_       struct{} `line:"Card {id}: {winning} | {picks}"`
+	ID      int
+	Winning []int
+	Picks   []int
+}
+
+// matches counts the picks that are winning numbers.
+func (c card) matches() int {
+	wins := make(map[int]bool)
+	for _, x := range c.Winning {
+		wins[x] = true
+	}
+	matches := 0
+	for _, x := range c.Picks {
+		if wins[x] {
+			matches++
+		}
+	}
+	return matches
+}
+
+// won is a batch of copies of a card won by an earlier card.
+type won struct {
+	from   int // ID of the winning card
+	copies number
+}
+
+// pending holds the copies won so far of a card not yet reached.
+type pending struct {
+	copies number
+	from   []won // kept only when explaining
+}
+
+// copyCounter tracks the cascade of copies won by each card in turn. Only
+// the copies pending for the next few cards are held, so any number of cards
+// can be counted in memory bounded by the most matches on a card.
+type copyCounter struct {
+	pending []pending // pending[i] is what the i-th next card has won so far
+	total   number
+	explain bool // keep where the pending copies came from
+}
+
+// add counts the next card, which has the given ID and number of matches,
+// and returns how many copies of it are held, and which earlier cards won
+// them if explaining.
+func (cc *copyCounter) add(id, matches int) (number, []won) {
+	copies := number{n: 1}
+	var from []won
+	if len(cc.pending) > 0 {
+		copies = copies.add(cc.pending[0].copies)
+		from = cc.pending[0].from
+		cc.pending = cc.pending[1:]
+	}
+	for len(cc.pending) < matches {
+		cc.pending = append(cc.pending, pending{})
+	}
+	for i := 0; i < matches; i++ {
+		p := &cc.pending[i]
+		p.copies = p.copies.add(copies)
+		if cc.explain {
+			p.from = append(p.from, won{id, copies})
+		}
+	}
+	wasBig := cc.total.isBig()
+	cc.total = cc.total.add(copies)
+	if !wasBig && cc.total.isBig() {
+		logger.Infof("Card %d: the number of cards outgrew an int, counting with big integers", id)
+	}
+	return copies, from
+}
+
+// count counts the copies of c.
+func (cc *copyCounter) count(c card) {
+	matches := c.matches()
+	copies, _ := cc.add(c.ID, matches)
+	logger.Debugf("Card %d: Matches: %d x %v copies", c.ID, matches, copies)
+}
+
+// report explains the copies of the cards picked by -explain.
+func report(cards []card) error {
+	if !explain.empty() {
+		writeExplanations(os.Stderr, cards, explain)
+	}
+	return nil
+}
+
+// writeExplanations counts the cards' copies, and lists which earlier cards
+// won the copies of each card in cs.
+func writeExplanations(w io.Writer, cards []card, cs *cardSet) {
+	cc := copyCounter{explain: true}
+	for _, c := range cards {
+		copies, from := cc.add(c.ID, c.matches())
+		if !cs.has(c.ID) {
+			continue
+		}
+		fmt.Fprintf(w, "Card %d: %v copies = 1 original", c.ID, copies)
+		for _, won := range from {
+			fmt.Fprintf(w, " + %v from card %d", won.copies, won.from)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func init() {
+	lib.Register(lib.Day{
+		Number: 4,
+		Parse:  lib.ParseFunc(parse),
+		Part1:  lib.PartFunc(part1),
+		Part2:  lib.PartFunc(part2),
+		Report: lib.ReportFunc(report),
+		Stream: stream,
+		Flags:  flags,
+	})
+}
+
+// parseCard reads a single "Card N: winning | picks" line.
+func parseCard(linen int, line string) (card, error) {
+	var c card
+	if err := lib.UnmarshalAt(lib.Pos{Line: linen}, line, &c); err != nil {
+		return card{}, err
+	}
+	logger.Debugf("Card %d: %v | %v", c.ID, c.Winning, c.Picks)
+	return c, nil
+}
+
+func parse(lines []string) ([]card, error) {
+	cards := make([]card, 0, len(lines))
+	for linen, line := range lines {
+		c, err := parseCard(linen+1, line)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, c)
+	}
+	return cards, nil
+}
+
+// points returns the points won by a card with the given number of matches.
+func points(matches int) number {
+	if matches == 0 {
+		return number{}
+	}
+	return pow2(matches - 1)
+}
+
+// part1 totals the points won by each card.
+func part1(cards []card) (string, error) {
+	var sum number
+	for _, c := range cards {
+		sum = sum.add(points(c.matches()))
+	}
+	logger.Infof("Total points: %v", sum)
+	return sum.String(), nil
+}
+
+// part2 counts the cards held once every win has produced its copies.
+func part2(cards []card) (string, error) {
+	var cc copyCounter
+	for _, c := range cards {
+		cc.count(c)
+	}
+	logger.Infof("Total cards: %v", cc.total)
+	return cc.total.String(), nil
+}
+
+// stream scores the cards for both parts in one pass, a card at a time.
+func stream(lines *lib.LineIterator) (string, string, error) {
+	var sum number
+	var cc copyCounter
+	for lines.Next() {
+		l := lines.Line()
+		c, err := parseCard(l.Num, l.Text)
+		if err != nil {
+			return "", "", err
+		}
+		sum = sum.add(points(c.matches()))
+		cc.count(c)
+	}
+	if err := lines.Err(); err != nil {
+		return "", "", err
+	}
+	logger.Infof("Total points: %v", sum)
+	logger.Infof("Total cards: %v", cc.total)
+	return sum.String(), cc.total.String(), nil
+}
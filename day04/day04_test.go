@@ -0,0 +1,105 @@
+package day04
+
+import (
+	"aoc23/lib/daytest"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestAnswers(t *testing.T) {
+	daytest.Answers(t, 4)
+}
+
+func BenchmarkParse(b *testing.B) { daytest.Bench(b, 4, "parse") }
+func BenchmarkPart1(b *testing.B) { daytest.Bench(b, 4, "part1") }
+func BenchmarkPart2(b *testing.B) { daytest.Bench(b, 4, "part2") }
+
+func TestAdd(t *testing.T) {
+	bigMax := number{big: big.NewInt(math.MaxInt)}
+	tests := []struct {
+		a, b  number
+		want  string
+		isBig bool
+	}{
+		{number{}, number{}, "0", false},
+		{number{n: math.MaxInt - 1}, number{n: 1}, strconv.Itoa(math.MaxInt), false},
+		{number{n: 1}, number{n: math.MaxInt - 1}, strconv.Itoa(math.MaxInt), false},
+		{number{n: math.MaxInt}, number{}, strconv.Itoa(math.MaxInt), false},
+		{number{n: math.MaxInt}, number{n: 1}, plusBig(math.MaxInt, 1), true},
+		{number{n: 1}, number{n: math.MaxInt}, plusBig(math.MaxInt, 1), true},
+		{number{n: math.MaxInt}, number{n: math.MaxInt}, plusBig(math.MaxInt, math.MaxInt), true},
+		{bigMax, number{n: 1}, plusBig(math.MaxInt, 1), true},
+		{number{}, bigMax, strconv.Itoa(math.MaxInt), true},
+	}
+	for _, tc := range tests {
+		got := tc.a.add(tc.b)
+		if got.String() != tc.want || got.isBig() != tc.isBig {
+			t.Errorf("%v.add(%v) = %v (big %t), want %s (big %t)", tc.a, tc.b, got, got.isBig(), tc.want, tc.isBig)
+		}
+	}
+}
+
+// plusBig returns a+b, computed without overflow.
+func plusBig(a, b int) string {
+	return new(big.Int).Add(big.NewInt(int64(a)), big.NewInt(int64(b))).String()
+}
+
+func TestPow2(t *testing.T) {
+	tests := []struct {
+		e     int
+		isBig bool
+	}{
+		{0, false},
+		{1, false},
+		{strconv.IntSize - 2, false},
+		{strconv.IntSize - 1, true},
+		{strconv.IntSize, true},
+		{100, true},
+	}
+	for _, tc := range tests {
+		got := pow2(tc.e)
+		want := new(big.Int).Lsh(big.NewInt(1), uint(tc.e))
+		if got.toBig().Cmp(want) != 0 || got.isBig() != tc.isBig {
+			t.Errorf("pow2(%d) = %v (big %t), want %v (big %t)", tc.e, got, got.isBig(), want, tc.isBig)
+		}
+	}
+}
+
+func TestExplanations(t *testing.T) {
+	cards, err := parse([]string{
+		"Card 1: 41 48 83 86 17 | 83 86  6 31 17  9 48 53",
+		"Card 2: 13 32 20 16 61 | 61 30 68 82 17 32 24 19",
+		"Card 3:  1 21 53 59 44 | 69 82 63 72 16 21 14  1",
+		"Card 4: 41 92 73 84 69 | 59 84 76 51 58  5 54 83",
+		"Card 5: 87 83 26 28 32 | 88 30 70 12 93 22 82 36",
+		"Card 6: 31 18 13 56 72 | 74 77 10 23 35 67 36 11",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"1", "Card 1: 1 copies = 1 original\n"},
+		{"3", "Card 3: 4 copies = 1 original + 1 from card 1 + 2 from card 2\n"},
+		{"5,6", "" +
+			"Card 5: 14 copies = 1 original + 1 from card 1 + 4 from card 3 + 8 from card 4\n" +
+			"Card 6: 1 copies = 1 original\n"},
+		{"7", ""},
+	}
+	for _, tc := range tests {
+		var cs cardSet
+		if err := cs.Set(tc.spec); err != nil {
+			t.Fatal(err)
+		}
+		var sb strings.Builder
+		writeExplanations(&sb, cards, &cs)
+		if got := sb.String(); got != tc.want {
+			t.Errorf("-explain %s:\ngot  %q\nwant %q", tc.spec, got, tc.want)
+		}
+	}
+}
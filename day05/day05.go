@@ -0,0 +1,486 @@
+package day05
+
+import (
+	"aoc23/lib"
+	"aoc23/lib/interval"
+	pc "aoc23/lib/parse"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	logger = lib.NewLogger("day05")
+
+	flags     = flag.NewFlagSet("day05", flag.ContinueOnError)
+	strict    = flags.Bool("strict", false, "treat gaps between the source ranges of a map as errors")
+	locations []interval.Interval
+)
+
+func init() {
+	flags.Func("locations", "write which seeds land in the locations LO-HI (half-open) to stderr, may be repeated", func(s string) error {
+		var lo, hi int
+		if _, err := fmt.Sscanf(s, "%d-%d", &lo, &hi); err != nil || lo > hi {
+			return fmt.Errorf("want LO-HI")
+		}
+		locations = append(locations, interval.Interval{Lo: lo, Hi: hi})
+		return nil
+	})
+}
+
+// maxID bounds the ids a map is defined over, leaving headroom so shifted
+// ranges cannot overflow.
+const maxID = int(lib.MaxUint >> 2)
+
+type thingRange struct {
+	srcLo int
+	dstLo int
+	count int
+	line  int // input line the range was read from, 0 if derived
+}
+
+func (tr thingRange) String() string {
+	return fmt.Sprintf(
+		"{src [%3d-%3d] => dst [%3d-%3d]",
+		tr.srcLo, tr.srcLo+tr.count-1,
+		tr.dstLo, tr.dstLo+tr.count-1,
+	)
+}
+
+// thingMap maps ids of one type of thing to another. Ids not covered by any
+// range map to themselves.
+type thingMap struct {
+	src, dst string
+	ranges   []*thingRange // sorted by srcLo
+}
+
+func (tm *thingMap) sortRanges() {
+	sort.SliceStable(tm.ranges, func(i, j int) bool {
+		return tm.ranges[i].srcLo < tm.ranges[j].srcLo
+	})
+}
+
+// Get maps srcThing[id] to dstThing[id].
+func (tm *thingMap) Get(id int) int {
+	// Find the first range that id could be in.
+	idx := sort.Search(len(tm.ranges), func(i int) bool {
+		return id < tm.ranges[i].srcLo+tm.ranges[i].count
+	})
+	if idx < len(tm.ranges) && id >= tm.ranges[idx].srcLo {
+		r := tm.ranges[idx]
+		return r.dstLo + (id - r.srcLo)
+	}
+	// No range holds id, so this is the identity mapping.
+	return id
+}
+
+// pieces returns the map as sorted, disjoint ranges covering every id in
+// [0, maxID), with the identity mapping made explicit. Where ranges overlap,
+// the one starting first wins.
+func (tm *thingMap) pieces() []*thingRange {
+	var res []*thingRange
+	pos := 0
+	for _, r := range tm.ranges {
+		lo, hi := lib.Max(r.srcLo, pos), r.srcLo+r.count
+		if lo >= hi {
+			continue
+		}
+		if lo > pos {
+			res = append(res, &thingRange{srcLo: pos, dstLo: pos, count: lo - pos})
+		}
+		res = append(res, &thingRange{srcLo: lo, dstLo: r.dstLo + (lo - r.srcLo), count: hi - lo})
+		pos = hi
+	}
+	if pos < maxID {
+		res = append(res, &thingRange{srcLo: pos, dstLo: pos, count: maxID - pos})
+	}
+	return res
+}
+
+// mapping returns the map as interval rules, so whole ranges of ids can be
+// sent through it at once.
+func (tm *thingMap) mapping() *interval.Mapping {
+	rules := make([]interval.Rule, len(tm.ranges))
+	for i, r := range tm.ranges {
+		rules[i] = interval.Rule{Src: r.srcLo, Dst: r.dstLo, Len: r.count}
+	}
+	return interval.NewMapping(rules...)
+}
+
+// Compose fuses a and b into a single map taking a's source type straight to
+// b's destination type, so that Compose(a, b).Get(id) == b.Get(a.Get(id)).
+func Compose(a, b *thingMap) *thingMap {
+	res := &thingMap{src: a.src, dst: b.dst}
+	bPieces := b.pieces()
+	for _, p := range a.pieces() {
+		img := interval.FromLen(p.dstLo, p.count)
+		// Split the image of each piece of a across the pieces of b.
+		i := sort.Search(len(bPieces), func(i int) bool {
+			return bPieces[i].srcLo+bPieces[i].count > img.Lo
+		})
+		for ; i < len(bPieces) && bPieces[i].srcLo < img.Hi; i++ {
+			q := bPieces[i]
+			sub := img.Intersect(interval.FromLen(q.srcLo, q.count))
+			srcLo := p.srcLo + (sub.Lo - p.dstLo)
+			dstLo := q.dstLo + (sub.Lo - q.srcLo)
+			if srcLo == dstLo {
+				continue // identity, left implicit
+			}
+			res.ranges = append(res.ranges, &thingRange{srcLo: srcLo, dstLo: dstLo, count: sub.Len()})
+		}
+	}
+	res.sortRanges()
+	return res
+}
+
+// Inverse returns the map from destination ids back to source ids. It is
+// only exact if the map is one-to-one; otherwise use Preimage.
+func (tm *thingMap) Inverse() *thingMap {
+	res := &thingMap{src: tm.dst, dst: tm.src}
+	for _, p := range tm.pieces() {
+		if p.srcLo != p.dstLo {
+			res.ranges = append(res.ranges, &thingRange{srcLo: p.dstLo, dstLo: p.srcLo, count: p.count})
+		}
+	}
+	res.sortRanges()
+	return res
+}
+
+// Preimage returns every source id that maps into dsts.
+func (tm *thingMap) Preimage(dsts interval.Set) interval.Set {
+	var res []interval.Interval
+	for _, p := range tm.pieces() {
+		hit := dsts.Intersect(interval.NewSet(interval.FromLen(p.dstLo, p.count)))
+		for _, iv := range hit.Intervals() {
+			res = append(res, iv.Shift(p.srcLo-p.dstLo))
+		}
+	}
+	return interval.NewSet(res...)
+}
+
+// printTable traces each id through every map in the pipeline.
+func printTable(pipeline []*thingMap, ids []int) {
+	if !logger.Enabled(lib.LevelTrace) {
+		return
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%15s", pipeline[0].src)
+	for _, tm := range pipeline {
+		fmt.Fprintf(&sb, "%15s", tm.dst)
+	}
+	sb.WriteString("\n")
+	for _, id := range ids {
+		fmt.Fprintf(&sb, "%15d", id)
+		for _, tm := range pipeline {
+			id = tm.Get(id)
+			fmt.Fprintf(&sb, "%15d", id)
+		}
+		sb.WriteString("\n")
+	}
+	logger.Tracef("Solution table:\n%s", sb.String())
+}
+
+// almanac holds the seeds and the maps from seed to location, in order.
+type almanac struct {
+	seeds    []int
+	pipeline []*thingMap
+	chain    *thingMap // the whole pipeline composed into one map
+}
+
+func init() {
+	lib.Register(lib.Day{
+		Number: 5,
+		Parse:  lib.ParseFunc(parse),
+		Part1:  lib.PartFunc(part1),
+		Part2:  lib.PartFunc(part2),
+		Report: lib.ReportFunc(report),
+		Flags:  flags,
+	})
+}
+
+// ErrorKind classifies the problems found in an almanac.
+type ErrorKind int
+
+const (
+	SyntaxError ErrorKind = iota
+	OverlapError
+	GapError
+	BranchError
+	CycleError
+	DeadEndError
+	UnreachableError
+)
+
+func (ek ErrorKind) String() string {
+	switch ek {
+	case SyntaxError:
+		return "syntax error"
+	case OverlapError:
+		return "overlapping ranges"
+	case GapError:
+		return "gap between ranges"
+	case BranchError:
+		return "branching maps"
+	case CycleError:
+		return "cycle"
+	case DeadEndError:
+		return "dead end"
+	case UnreachableError:
+		return "unreachable location"
+	default:
+		return "invalid error kind"
+	}
+}
+
+// AlmanacError is a single problem found while parsing an almanac.
+type AlmanacError struct {
+	Kind ErrorKind
+	*lib.ParseError
+}
+
+func (e *AlmanacError) Unwrap() error {
+	return e.ParseError
+}
+
+// mapHeader is the "<src>-to-<dst> map:" line starting a map.
+type mapHeader struct {
+	src, dst string
+}
+
+// grammar is the layout of an almanac: a line of seeds, then blocks of maps,
+// each a header followed by "<dst> <src> <count>" ranges.
+var grammar = func() pc.Parser[rawAlmanac] {
+	id := pc.At(pc.Int())
+	seeds := pc.Right(pc.Literal("seeds:"), pc.Many(pc.Right(pc.Spaces(), id)))
+	header := pc.Seq2(
+		pc.Word(),
+		pc.Right(pc.Literal("-to-"), pc.Left(pc.Word(), pc.Literal(" map:"))),
+		func(src, dst string) mapHeader { return mapHeader{src, dst} },
+	)
+	triple := pc.Seq3(id, pc.Right(pc.Spaces(), id), pc.Right(pc.Spaces(), id),
+		func(dst, src, count pc.Located[int]) [3]pc.Located[int] {
+			return [3]pc.Located[int]{dst, src, count}
+		},
+	)
+	maps := pc.Blocks(pc.At(pc.Section(header, triple)))
+	return pc.Seq2(
+		pc.Left(seeds, pc.LineEnd()),
+		pc.Right(pc.Many(pc.Newline()), maps),
+		func(seeds []pc.Located[int], maps []pc.Located[rawMap]) rawAlmanac {
+			return rawAlmanac{seeds, maps}
+		},
+	)
+}()
+
+// rawMap and rawAlmanac are an almanac as read, before validation.
+type (
+	rawMap     = pc.Titled[mapHeader, [3]pc.Located[int]]
+	rawAlmanac struct {
+		seeds []pc.Located[int]
+		maps  []pc.Located[rawMap]
+	}
+)
+
+// parse reads and validates the almanac. All problems found are returned
+// together, each as an *AlmanacError.
+func parse(lines []string) (*almanac, error) {
+	raw, err := pc.Run(grammar, strings.Join(lines, ""))
+	var pe *lib.ParseError
+	if errors.As(err, &pe) {
+		pe.Err = fmt.Errorf("%v: %w", SyntaxError, pe.Err)
+		return nil, &AlmanacError{SyntaxError, pe}
+	}
+
+	var errs []error
+	addErr := func(kind ErrorKind, pos lib.Pos, msgfmt string, args ...any) {
+		errs = append(errs, &AlmanacError{kind, pos.Errorf("", "%v: %s", kind, fmt.Sprintf(msgfmt, args...))})
+	}
+	idOK := func(n pc.Located[int]) bool {
+		if n.Value < 0 || n.Value >= maxID {
+			addErr(SyntaxError, n.Pos, "id %d out of range", n.Value)
+			return false
+		}
+		return true
+	}
+
+	var seeds []int
+	for _, n := range raw.seeds {
+		if idOK(n) {
+			seeds = append(seeds, n.Value)
+		}
+	}
+
+	var (
+		maps   []*thingMap
+		bySrc  = make(map[string]*thingMap)
+		header = make(map[*thingMap]int) // line of each map's header
+	)
+	for _, m := range raw.maps {
+		curMap := &thingMap{src: m.Value.Header.src, dst: m.Value.Header.dst}
+		header[curMap] = m.Pos.Line
+		maps = append(maps, curMap)
+		if prev, ok := bySrc[curMap.src]; ok {
+			addErr(BranchError, m.Pos, "second map from %s (first on line %d)", curMap.src, header[prev])
+		} else {
+			bySrc[curMap.src] = curMap
+		}
+
+		for _, t := range m.Value.Items {
+			dstLo, srcLo, count := t[0], t[1], t[2]
+			if ok1, ok2, ok3 := idOK(dstLo), idOK(srcLo), idOK(count); !ok1 || !ok2 || !ok3 {
+				continue
+			}
+			if count.Value == 0 {
+				addErr(SyntaxError, count.Pos, "empty range")
+				continue
+			}
+			if srcLo.Value+count.Value > maxID || dstLo.Value+count.Value > maxID {
+				addErr(SyntaxError, count.Pos, "range too large")
+				continue
+			}
+			curMap.ranges = append(curMap.ranges, &thingRange{
+				dstLo: dstLo.Value,
+				srcLo: srcLo.Value,
+				count: count.Value,
+				line:  count.Pos.Line,
+			})
+		}
+	}
+
+	// Check the ranges of each map. Each range is compared with the furthest
+	// any earlier range reaches, not just the one before it, which may be
+	// nested inside a longer one.
+	for _, tm := range maps {
+		tm.sortRanges()
+		logger.Debugf("%s-to-%s", tm.src, tm.dst)
+		var maxEnd, maxLine int
+		for i, r := range tm.ranges {
+			logger.Debugf("\t%+v", r)
+			if i > 0 && r.srcLo < maxEnd {
+				addErr(OverlapError, lib.Pos{Line: r.line}, "%s-to-%s source range %v overlaps line %d",
+					tm.src, tm.dst, interval.FromLen(r.srcLo, r.count), maxLine)
+			} else if i > 0 && r.srcLo > maxEnd {
+				if *strict {
+					addErr(GapError, lib.Pos{Line: r.line}, "%s-to-%s source ids [%d,%d) are not covered",
+						tm.src, tm.dst, maxEnd, r.srcLo)
+				} else {
+					logger.Infof("line %d: %s-to-%s source ids [%d,%d) are not covered (identity)",
+						r.line, tm.src, tm.dst, maxEnd, r.srcLo)
+				}
+			}
+			if end := r.srcLo + r.count; end > maxEnd {
+				maxEnd, maxLine = end, r.line
+			}
+		}
+	}
+
+	// Check the type graph: each type other than location must lead on to
+	// another, without coming back round.
+	for _, tm := range maps {
+		if _, ok := bySrc[tm.dst]; !ok && tm.dst != "location" {
+			addErr(DeadEndError, lib.Pos{Line: header[tm]}, "nothing maps onward from %s", tm.dst)
+		}
+	}
+	for _, tm := range maps {
+		if bySrc[tm.src] != tm {
+			continue // already reported as a branch
+		}
+		seen := map[string]bool{tm.src: true}
+		for next := bySrc[tm.dst]; next != nil && next.src != "location"; next = bySrc[next.dst] {
+			if next == tm {
+				// Report each cycle once, at its first header.
+				first := true
+				for c := bySrc[tm.dst]; c != tm; c = bySrc[c.dst] {
+					first = first && header[tm] < header[c]
+				}
+				if first {
+					addErr(CycleError, lib.Pos{Line: header[tm]}, "maps from %s loop back to %s", tm.src, tm.src)
+				}
+				break
+			}
+			if seen[next.src] {
+				break // a cycle further along, reported from there
+			}
+			seen[next.src] = true
+		}
+	}
+
+	// Order the maps into a pipeline from seed to location.
+	var pipeline []*thingMap
+	used := make(map[*thingMap]bool)
+	for t := "seed"; t != "location"; {
+		tm, ok := bySrc[t]
+		if !ok || used[tm] {
+			addErr(UnreachableError, lib.Pos{}, "no path of maps from seed to location")
+			pipeline = nil
+			break
+		}
+		used[tm] = true
+		pipeline = append(pipeline, tm)
+		t = tm.dst
+	}
+	for _, tm := range maps {
+		if !used[tm] && pipeline != nil {
+			logger.Infof("line %d: %s-to-%s map is never used", header[tm], tm.src, tm.dst)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	chain := pipeline[0]
+	for _, tm := range pipeline[1:] {
+		chain = Compose(chain, tm)
+	}
+	logger.Debugf("seed-to-location (composed): %d ranges", len(chain.ranges))
+
+	logger.Debugf("Seeds: %v", seeds)
+	return &almanac{seeds: seeds, pipeline: pipeline, chain: chain}, nil
+}
+
+// report answers the -locations reverse queries.
+func report(a *almanac) error {
+	for _, loc := range locations {
+		fmt.Fprintf(os.Stderr, "Seeds landing in locations %v: %v\n", loc, a.chain.Preimage(interval.NewSet(loc)))
+	}
+	return nil
+}
+
+// part1 finds the lowest location of any listed seed: just send it.
+func part1(a *almanac) (string, error) {
+	printTable(a.pipeline, a.seeds)
+	min := lib.MaxUint
+	for _, seed := range a.seeds {
+		min = lib.Min(uint(a.chain.Get(seed)), min)
+	}
+	logger.Infof("Minimum location (part 1): %d", min)
+	return strconv.FormatUint(uint64(min), 10), nil
+}
+
+// part2 treats the seeds as (start, length) ranges, and sends the whole set
+// of ranges through the composed map at once.
+func part2(a *almanac) (string, error) {
+	seeds := a.seeds
+	if len(seeds)%2 != 0 {
+		return "", fmt.Errorf("unbalanced seed count: must be even, got %d", len(seeds))
+	}
+	var ivs []interval.Interval
+	for i := 0; i < len(seeds); i += 2 {
+		ivs = append(ivs, interval.FromLen(seeds[i], seeds[i+1]))
+	}
+	set := a.chain.mapping().Apply(interval.NewSet(ivs...))
+	logger.Tracef("Locations: %v", set)
+
+	min, ok := set.Min()
+	if !ok {
+		return "", fmt.Errorf("no seeds")
+	}
+	logger.Infof("Minimum location (part 2): %d", min)
+	return strconv.Itoa(min), nil
+}
@@ -0,0 +1,117 @@
+package day05
+
+import (
+	"aoc23/lib"
+	"aoc23/lib/daytest"
+	"aoc23/lib/interval"
+	"fmt"
+	"testing"
+)
+
+func TestAnswers(t *testing.T) {
+	daytest.Answers(t, 5)
+}
+
+func BenchmarkParse(b *testing.B) { daytest.Bench(b, 5, "parse") }
+func BenchmarkPart1(b *testing.B) { daytest.Bench(b, 5, "part1") }
+func BenchmarkPart2(b *testing.B) { daytest.Bench(b, 5, "part2") }
+
+func loadSample(t *testing.T) *almanac {
+	t.Helper()
+	lines, err := lib.GetInputFileAll("input-sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := parse(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestCompose(t *testing.T) {
+	a := loadSample(t)
+	for id := 0; id < 200; id++ {
+		want := id
+		for _, tm := range a.pipeline {
+			want = tm.Get(want)
+		}
+		if got := a.chain.Get(id); got != want {
+			t.Errorf("chain.Get(%d) = %d, want %d", id, got, want)
+		}
+	}
+}
+
+func TestInverse(t *testing.T) {
+	a := loadSample(t)
+	inv := a.chain.Inverse()
+	if inv.src != "location" || inv.dst != "seed" {
+		t.Errorf("Inverse maps %s to %s, want location to seed", inv.src, inv.dst)
+	}
+	for id := 0; id < 200; id++ {
+		loc := a.chain.Get(id)
+		if got := inv.Get(loc); got != id {
+			t.Errorf("Inverse().Get(%d) = %d, want %d", loc, got, id)
+		}
+	}
+}
+
+func TestPreimage(t *testing.T) {
+	a := loadSample(t)
+	tests := []struct {
+		locs interval.Interval
+		want string
+	}{
+		{interval.Interval{Lo: 46, Hi: 47}, "{[82,83)}"},
+		{interval.Interval{Lo: 35, Hi: 36}, "{[13,14)}"},
+		{interval.Interval{Lo: 5, Hi: 5}, "{}"},
+	}
+	for _, tc := range tests {
+		got := a.chain.Preimage(interval.NewSet(tc.locs))
+		if got.String() != tc.want {
+			t.Errorf("Preimage(%v) = %v, want %s", tc.locs, got, tc.want)
+		}
+		for _, iv := range got.Intervals() {
+			for id := iv.Lo; id < iv.Hi; id++ {
+				if loc := a.chain.Get(id); !tc.locs.Contains(loc) {
+					t.Errorf("Preimage(%v) holds %d, which maps to %d", tc.locs, id, loc)
+				}
+			}
+		}
+	}
+}
+
+func TestCheckRanges(t *testing.T) {
+	*strict = true
+	defer func() { *strict = false }()
+
+	tests := []struct {
+		name   string
+		ranges []string
+		want   []ErrorKind
+	}{
+		{"adjacent", []string{"0 0 10", "50 10 10"}, nil},
+		{"gap", []string{"0 0 10", "50 20 10"}, []ErrorKind{GapError}},
+		{"overlap", []string{"0 0 10", "50 5 10"}, []ErrorKind{OverlapError}},
+		// [30,40) lies inside [0,100), two ranges back.
+		{"nested", []string{"0 0 100", "200 10 10", "300 30 10"}, []ErrorKind{OverlapError, OverlapError}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lines := []string{"seeds: 1\n", "\n", "seed-to-location map:\n"}
+			for _, r := range tc.ranges {
+				lines = append(lines, r+"\n")
+			}
+			_, err := parse(lines)
+			var got []ErrorKind
+			if err != nil {
+				for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+					got = append(got, e.(*AlmanacError).Kind)
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+				t.Errorf("got errors %v (%v), want %v", got, err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package day06
+
+import (
+	"aoc23/lib"
+	"flag"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	logger = lib.NewLogger("day06")
+
+	flags = flag.NewFlagSet("day06", flag.ContinueOnError)
+	check = flags.Bool("check", false, "cross-check every race against a linear scan of the hold times")
+)
+
+type raceInfo struct {
+	raceDuration   *big.Int
+	recordDistance *big.Int
+}
+
+// NumWaysToWin counts the hold times that beat the record. Holding for h
+// goes h*(T-h), which beats D when h^2 - T*h + D < 0, so the winning holds
+// lie strictly between the roots (T +- sqrt(T^2 - 4D)) / 2. A hold that only
+// ties the record does not count.
+func (ri raceInfo) NumWaysToWin() *big.Int {
+	T, D := ri.raceDuration, ri.recordDistance
+	one := big.NewInt(1)
+	beats := func(h *big.Int) bool {
+		dist := new(big.Int).Sub(T, h)
+		return dist.Mul(dist, h).Cmp(D) > 0
+	}
+
+	disc := new(big.Int).Mul(T, T)
+	disc.Sub(disc, new(big.Int).Lsh(D, 2))
+	if disc.Sign() < 0 {
+		return new(big.Int)
+	}
+
+	// Start from the integer estimate of the lower root, and step to the
+	// first winning hold. It is at most a step or two away, unless no hold
+	// wins at all.
+	half := new(big.Int).Rsh(T, 1)
+	lo := new(big.Int).Sub(T, new(big.Int).Sqrt(disc))
+	lo.Rsh(lo, 1)
+	for lo.Cmp(half) <= 0 && !beats(lo) {
+		lo.Add(lo, one)
+	}
+	for lo.Sign() > 0 && beats(new(big.Int).Sub(lo, one)) {
+		lo.Sub(lo, one)
+	}
+	if !beats(lo) {
+		return new(big.Int)
+	}
+
+	// The distances are symmetric, so the last winning hold is T - lo.
+	n := new(big.Int).Sub(T, lo)
+	return n.Sub(n, lo).Add(n, one)
+}
+
+func DistForHold(holdTime, totalDuration int64) int64 {
+	speed := holdTime
+	movingDuration := totalDuration - holdTime
+	return movingDuration * speed
+}
+
+// maxScanDuration bounds the races scanned by scanWaysToWin, so DistForHold
+// cannot overflow.
+const maxScanDuration = 1 << 31
+
+func MinWinHold(duration, record int64) int64 {
+	for i := int64(0); i <= duration; i++ {
+		myDistance := DistForHold(i, duration)
+		if myDistance > record {
+			return i
+		}
+	}
+	return -1
+}
+
+func MaxWinHold(duration, record int64) int64 {
+	for i := duration; i >= 0; i-- {
+		myDistance := DistForHold(i, duration)
+		if myDistance > record {
+			return i
+		}
+	}
+	return -1
+}
+
+// scanWaysToWin counts the winning hold times by trying them in turn from
+// either end, to check NumWaysToWin.
+func (ri raceInfo) scanWaysToWin() (*big.Int, error) {
+	if !ri.raceDuration.IsInt64() || ri.raceDuration.Int64() > maxScanDuration || !ri.recordDistance.IsInt64() {
+		return nil, fmt.Errorf("race of %v ms is too long to scan", ri.raceDuration)
+	}
+	duration, record := ri.raceDuration.Int64(), ri.recordDistance.Int64()
+	min := MinWinHold(duration, record)
+	if min < 0 {
+		return new(big.Int), nil
+	}
+	return big.NewInt(MaxWinHold(duration, record) - min + 1), nil
+}
+
+// waysToWin returns NumWaysToWin, cross-checked by a scan if requested.
+func (ri raceInfo) waysToWin() (*big.Int, error) {
+	n := ri.NumWaysToWin()
+	if !*check {
+		return n, nil
+	}
+	scanned, err := ri.scanWaysToWin()
+	if err != nil {
+		return nil, err
+	}
+	if scanned.Cmp(n) != 0 {
+		return nil, fmt.Errorf("race of %v ms, record %v mm: closed form gives %v ways to win, scan gives %v",
+			ri.raceDuration, ri.recordDistance, n, scanned)
+	}
+	logger.Debugf("Race of %v ms: scan agrees on %v ways to win", ri.raceDuration, n)
+	return n, nil
+}
+
+type raceSheet struct {
+	races   []raceInfo // part 1: races are separate
+	bigRace raceInfo   // part 2: bad kerning single race
+}
+
+func init() {
+	lib.Register(lib.Day{
+		Number: 6,
+		Parse:  lib.ParseFunc(parse),
+		Part1:  lib.PartFunc(part1),
+		Part2:  lib.PartFunc(part2),
+		Flags:  flags,
+	})
+}
+
+// parseRow reads the numbers following label on a line, both as separate
+// numbers and as one number with the spaces removed, which may be of any
+// size.
+func parseRow(linen int, line, label string) ([]*big.Int, *big.Int, error) {
+	pos := lib.Pos{Line: linen, Column: 1}
+	line = strings.TrimRight(line, "\r\n")
+	if !strings.HasPrefix(line, label) {
+		return nil, nil, pos.Errorf(line, "expected %q", label)
+	}
+	fields := lib.Fields(line[len(label):])
+	if len(fields) == 0 {
+		return nil, nil, pos.Errorf(line, "no numbers")
+	}
+	nums := make([]*big.Int, len(fields))
+	var joined strings.Builder
+	for i, f := range fields {
+		fpos := pos.Offset(utf8.RuneCountInString(label) + f.Col - 1)
+		n, err := lib.ParseBigInt(fpos, f.Text)
+		if err != nil {
+			return nil, nil, err
+		}
+		if n.Sign() < 0 {
+			return nil, nil, fpos.Errorf(f.Text, "negative number")
+		}
+		nums[i] = n
+		joined.WriteString(n.String())
+	}
+	all, _ := new(big.Int).SetString(joined.String(), 10)
+	return nums, all, nil
+}
+
+func parse(lines []string) (*raceSheet, error) {
+	if len(lines) < 2 {
+		return nil, fmt.Errorf("expected 2 lines of input, got %d", len(lines))
+	}
+
+	logger.Debugf("line 1: %q", lines[0])
+	logger.Debugf("line 2: %q", lines[1])
+
+	times, time, err := parseRow(1, lines[0], "Time:")
+	if err != nil {
+		return nil, err
+	}
+	distances, distance, err := parseRow(2, lines[1], "Distance:")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(times) != len(distances) {
+		return nil, fmt.Errorf("got %d times but %d distances", len(times), len(distances))
+	}
+	sheet := &raceSheet{
+		races: make([]raceInfo, len(times)),
+	}
+	for i := 0; i < len(times); i++ {
+		sheet.races[i] = raceInfo{
+			raceDuration:   times[i],
+			recordDistance: distances[i],
+		}
+	}
+
+	logger.Debugf("Time: %v", time)
+	logger.Debugf("Dist: %v", distance)
+	sheet.bigRace = raceInfo{
+		raceDuration:   time,
+		recordDistance: distance,
+	}
+	return sheet, nil
+}
+
+func part1(sheet *raceSheet) (string, error) {
+	margin := big.NewInt(1)
+	for i, r := range sheet.races {
+		n, err := r.waysToWin()
+		if err != nil {
+			return "", err
+		}
+		logger.Debugf("Ways to win race %d: %v", i, n)
+		margin.Mul(margin, n)
+	}
+	if margin.Sign() == 0 {
+		logger.Infof("Cannot win every race")
+	} else {
+		logger.Infof("Margin to win: %v", margin)
+	}
+	return margin.String(), nil
+}
+
+func part2(sheet *raceSheet) (string, error) {
+	n, err := sheet.bigRace.waysToWin()
+	if err != nil {
+		return "", err
+	}
+	logger.Infof("Ways to win big race: %v", n)
+	return n.String(), nil
+}
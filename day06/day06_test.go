@@ -0,0 +1,14 @@
+package day06
+
+import (
+	"aoc23/lib/daytest"
+	"testing"
+)
+
+func TestAnswers(t *testing.T) {
+	daytest.Answers(t, 6)
+}
+
+func BenchmarkParse(b *testing.B) { daytest.Bench(b, 6, "parse") }
+func BenchmarkPart1(b *testing.B) { daytest.Bench(b, 6, "part1") }
+func BenchmarkPart2(b *testing.B) { daytest.Bench(b, 6, "part2") }
@@ -0,0 +1,464 @@
+package day07
+
+import (
+	"aoc23/lib"
+	"flag"
+	"fmt"
+	"math/bits"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	logger = lib.NewLogger("day07")
+
+	flags      = flag.NewFlagSet("day07", flag.ContinueOnError)
+	order      = flags.String("order", "23456789TJQKA", "cards from weakest to strongest")
+	handSize   = flags.Int("size", 5, "cards per hand")
+	categories = flags.String("categories", "camel", "hand categories: camel, poker, or group sizes weakest first (e.g. 1,2,22,3,32,4,5)")
+	wild       = flags.String("wild", "J", "wild cards in part 2")
+	wildLow    = flags.Bool("wildlow", true, "wild cards are the weakest cards when breaking ties, kept in their -order order")
+)
+
+// category is a kind of hand, defined by the groups of matching cards it
+// needs, largest first: a full house needs groups of 3 and 2.
+type category struct {
+	name   string
+	groups []int
+}
+
+// categorySets are the named sets of categories, weakest first. Categories
+// only depend on the counts of each card, so poker has no straights or
+// flushes.
+var categorySets = map[string][]category{
+	"camel": {
+		{"high-card", []int{1}},
+		{"one-pair", []int{2}},
+		{"two-pair", []int{2, 2}},
+		{"three-of-a-kind", []int{3}},
+		{"full-house", []int{3, 2}},
+		{"four-of-a-kind", []int{4}},
+		{"five-of-a-kind", []int{5}},
+	},
+	"poker": {
+		{"high-card", []int{1}},
+		{"one-pair", []int{2}},
+		{"two-pair", []int{2, 2}},
+		{"three-of-a-kind", []int{3}},
+		{"full-house", []int{3, 2}},
+		{"four-of-a-kind", []int{4}},
+	},
+}
+
+// parseCategories reads a set of categories by name, or as comma separated
+// group sizes such as "32" for a full house.
+func parseCategories(spec string) ([]category, error) {
+	if cats, ok := categorySets[spec]; ok {
+		return cats, nil
+	}
+	var cats []category
+	for _, f := range strings.Split(spec, ",") {
+		f = strings.TrimSpace(f)
+		cat := category{name: f}
+		for i, r := range f {
+			g, err := lib.ParseDigit(lib.Pos{Column: i + 1}, r)
+			if err != nil || g == 0 {
+				return nil, fmt.Errorf("invalid category %q in %q: want group sizes, e.g. 32", f, spec)
+			}
+			cat.groups = append(cat.groups, g)
+		}
+		if len(cat.groups) == 0 {
+			return nil, fmt.Errorf("empty category in %q", spec)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(cat.groups)))
+		cats = append(cats, cat)
+	}
+	return cats, nil
+}
+
+// rules are a variant of the game.
+type rules struct {
+	order      string     // cards from weakest to strongest
+	wild       string     // cards that stand in for any other
+	wildLow    bool       // whether wild cards are the weakest in tiebreaks
+	size       int        // cards per hand
+	categories []category // weakest first
+
+	cardBits uint       // bits per card in a sort key
+	values   [256]int16 // each card's strength in tiebreaks, -1 if not in order
+}
+
+// newRules builds the rules set by the flags, with the given wild cards.
+func newRules(wild string) (*rules, error) {
+	r := &rules{order: *order, wild: wild, wildLow: *wildLow, size: *handSize}
+	if r.size <= 0 {
+		return nil, fmt.Errorf("invalid hand size %d", r.size)
+	}
+	for i, c := range r.order {
+		if strings.ContainsRune(r.order[i+1:], c) {
+			return nil, fmt.Errorf("card %c listed twice in order %q", c, r.order)
+		}
+	}
+	for _, c := range r.wild {
+		if !strings.ContainsRune(r.order, c) {
+			return nil, fmt.Errorf("wild card %c is not in order %q", c, r.order)
+		}
+	}
+	var err error
+	if r.categories, err = parseCategories(*categories); err != nil {
+		return nil, err
+	}
+	for _, cat := range r.categories {
+		n := 0
+		for _, g := range cat.groups {
+			n += g
+		}
+		if n > r.size {
+			return nil, fmt.Errorf("category %s needs %d cards, but hands have %d", cat.name, n, r.size)
+		}
+	}
+
+	// With wildLow, the wild cards rank below the rest in tiebreaks, but keep
+	// their order among themselves so that distinct hands never tie.
+	for i := range r.values {
+		r.values[i] = -1
+	}
+	v := int16(0)
+	if r.wildLow {
+		for i := 0; i < len(r.order); i++ {
+			if c := card(r.order[i]); r.isWild(c) {
+				r.values[c] = v
+				v++
+			}
+		}
+	}
+	for i := 0; i < len(r.order); i++ {
+		if c := card(r.order[i]); !r.wildLow || !r.isWild(c) {
+			r.values[c] = v
+			v++
+		}
+	}
+
+	// Sort keys hold the category, then each card's value, all offset by
+	// one so that -1 packs as 0.
+	r.cardBits = uint(bits.Len(uint(len(r.order))))
+	if total := uint(bits.Len(uint(len(r.categories)))) + uint(r.size)*r.cardBits; total > 64 {
+		return nil, fmt.Errorf("hands of %d cards need %d-bit sort keys, more than 64", r.size, total)
+	}
+	return r, nil
+}
+
+func (r *rules) isWild(c card) bool {
+	return strings.IndexByte(r.wild, byte(c)) >= 0
+}
+
+// value returns the strength of c when breaking ties.
+func (r *rules) value(c card) int {
+	return int(r.values[c])
+}
+
+// classify returns the index of the strongest category the cards make, or -1
+// if they make none. Wild cards join whichever groups need them.
+func (r *rules) classify(cards string) int {
+	var seen [256]int
+	wilds := 0
+	for i := 0; i < len(cards); i++ {
+		if c := card(cards[i]); r.isWild(c) {
+			wilds++
+		} else {
+			seen[c]++
+		}
+	}
+	counts := make([]int, 0, len(cards))
+	for i := 0; i < len(cards); i++ {
+		if n := seen[cards[i]]; n > 0 {
+			counts = append(counts, n)
+			seen[cards[i]] = 0
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(counts)))
+
+	// Matching the largest groups needed with the largest groups held needs
+	// the fewest wild cards to fill the gaps.
+	for i := len(r.categories) - 1; i >= 0; i-- {
+		need := 0
+		for j, g := range r.categories[i].groups {
+			have := 0
+			if j < len(counts) {
+				have = counts[j]
+			}
+			need += lib.Max(0, g-have)
+		}
+		if need <= wilds {
+			return i
+		}
+	}
+	return -1
+}
+
+type card byte
+
+func (c card) String() string {
+	return string(c)
+}
+
+type hand struct {
+	cards string
+	bid   int
+	rules *rules // nil until dealt under a set of rules
+	key   uint64 // orders hands by strength under rules
+}
+
+// deal returns a hand under the rules, with its sort key.
+func (r *rules) deal(cards string, bid int) *hand {
+	key := uint64(r.classify(cards) + 1)
+	for i := 0; i < len(cards); i++ {
+		key = key<<r.cardBits | uint64(r.value(card(cards[i]))+1)
+	}
+	return &hand{cards: cards, bid: bid, rules: r, key: key}
+}
+
+func (h hand) String() string {
+	if h.rules == nil {
+		return fmt.Sprintf("{%s %4d}", h.cards, h.bid)
+	}
+	return fmt.Sprintf("{%s %4d %s}", h.cards, h.bid, h.Category())
+}
+
+// Rank returns the index of the hand's category under its rules, stronger
+// hands ranking higher, or -1 if it has none or has not been dealt.
+func (h *hand) Rank() int {
+	if h.rules == nil {
+		return -1
+	}
+	return int(h.key>>(uint(len(h.cards))*h.rules.cardBits)) - 1
+}
+
+// Category returns the name of the hand's category.
+func (h *hand) Category() string {
+	if i := h.Rank(); i >= 0 {
+		return h.rules.categories[i].name
+	}
+	return "none"
+}
+
+// compareHands returns -1, 0 or +1 as a is weaker than, ties with, or beats
+// b. Hands with the same cards tie.
+func compareHands(a, b *hand) int {
+	switch {
+	case a.key < b.key:
+		return -1
+	case a.key > b.key:
+		return 1
+	}
+	return 0
+}
+
+// handGroup collects every dealt copy of one hand. Identical hands rank in
+// the order they were dealt, so the group's winnings only depend on its bids
+// and on weighted, the sum of each bid times the copies dealt before it.
+type handGroup struct {
+	hand     *hand
+	n        int
+	bids     int
+	weighted int
+}
+
+// handGroups holds the groups of dealt hands, keyed by their cards. There
+// are at most 13^5 distinct five-card hands, however many are dealt.
+type handGroups map[string]*handGroup
+
+func (hg handGroups) add(h *hand) {
+	key := h.cards
+	g, ok := hg[key]
+	if !ok {
+		g = &handGroup{hand: h}
+		hg[key] = g
+	}
+	g.weighted += g.n * h.bid
+	g.n++
+	g.bids += h.bid
+}
+
+// winnings ranks the distinct hands and tallies the score.
+func (hg handGroups) winnings(r *rules) int {
+	type dealt struct {
+		hand  *hand
+		group *handGroup
+	}
+	ranked := make([]dealt, 0, len(hg))
+	for _, g := range hg {
+		ranked = append(ranked, dealt{r.deal(g.hand.cards, g.hand.bid), g})
+	}
+	// Distinct hands can tie under some rules, and map order is random, so
+	// fall back to the cards to rank them the same way every run.
+	sort.Slice(ranked, func(i, j int) bool {
+		if c := compareHands(ranked[i].hand, ranked[j].hand); c != 0 {
+			return c < 0
+		}
+		return ranked[i].hand.cards < ranked[j].hand.cards
+	})
+
+	debug := logger.Enabled(lib.LevelDebug)
+	score := 0
+	rank := 1
+	for _, d := range ranked {
+		g := d.group
+		pts := rank*g.bids + g.weighted
+		score += pts
+		if debug {
+			logger.Debugf("Hands %d-%d: %s x%d, => %d", rank, rank+g.n-1, d.hand, g.n, pts)
+		}
+		rank += g.n
+	}
+	return score
+}
+
+func init() {
+	lib.Register(lib.Day{
+		Number:   7,
+		Parse:    lib.ParseFunc(parse),
+		Part1:    lib.PartFunc(part1),
+		Part2:    lib.PartFunc(part2),
+		Stream:   stream,
+		Generate: generate,
+		Flags:    flags,
+	})
+}
+
+// handLine is the layout of a dealt hand.
+type handLine struct {
+	_     struct{} `line:"{cards} {bid}"`
+	Cards string
+	Bid   int
+}
+
+// parseHand reads a single "<cards> <bid>" line. Well-formed lines are split
+// by hand, which is much faster than unmarshalling millions of them; anything
+// else is left to lib.UnmarshalAt to report.
+func parseHand(linen int, line string) (*hand, error) {
+	pos := lib.Pos{Line: linen}
+	cards, bid, ok := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+	n, err := strconv.Atoi(bid)
+	if !ok || err != nil || cards == "" {
+		var hl handLine
+		if err := lib.UnmarshalAt(pos, line, &hl); err != nil {
+			return nil, err
+		}
+		cards, n = hl.Cards, hl.Bid
+	}
+	if len(cards) != *handSize {
+		return nil, pos.Errorf(cards, "hand has %d cards, want %d", len(cards), *handSize)
+	}
+
+	col := 0
+	for _, c := range cards {
+		if c >= utf8.RuneSelf || strings.IndexByte(*order, byte(c)) < 0 {
+			return nil, pos.Offset(col).Errorf(string(c), "unknown card")
+		}
+		col++
+	}
+	return &hand{cards: cards, bid: n}, nil
+}
+
+// generate deals n random hands under the card order and hand size flags.
+func generate(n int, seed int64) []string {
+	rng := rand.New(rand.NewSource(seed))
+	lines := make([]string, n)
+	cards := make([]byte, *handSize)
+	for i := range lines {
+		for j := range cards {
+			cards[j] = (*order)[rng.Intn(len(*order))]
+		}
+		lines[i] = fmt.Sprintf("%s %d\n", cards, 1+rng.Intn(1000))
+	}
+	return lines
+}
+
+func parse(lines []string) ([]*hand, error) {
+	hands := make([]*hand, 0, len(lines))
+	for linen, line := range lines {
+		h, err := parseHand(linen+1, line)
+		if err != nil {
+			return nil, err
+		}
+		hands = append(hands, h)
+	}
+
+	if logger.Enabled(lib.LevelDebug) {
+		logger.Debugf("As dealt:")
+		for i, h := range hands {
+			logger.Debugf("Hand %d: %+v", i, h)
+		}
+	}
+	return hands, nil
+}
+
+// totalWinnings groups the hands and tallies the score, with wild as the
+// wild cards.
+func totalWinnings(dealt []*hand, wild string) (int, error) {
+	r, err := newRules(wild)
+	if err != nil {
+		return 0, err
+	}
+	hg := make(handGroups)
+	for _, h := range dealt {
+		hg.add(h)
+	}
+	return hg.winnings(r), nil
+}
+
+func part1(hands []*hand) (string, error) {
+	score, err := totalWinnings(hands, "")
+	if err != nil {
+		return "", err
+	}
+	logger.Infof("Part 1 score: %d", score)
+	return strconv.Itoa(score), nil
+}
+
+// part2 scores the hands again with wild cards (jokers) activated.
+func part2(hands []*hand) (string, error) {
+	score, err := totalWinnings(hands, *wild)
+	if err != nil {
+		return "", err
+	}
+	logger.Infof("Part 2 score: %d", score)
+	return strconv.Itoa(score), nil
+}
+
+// stream groups the hands as they are dealt, holding only the distinct
+// hands, and scores both parts at the end.
+func stream(lines *lib.LineIterator) (string, string, error) {
+	r1, err := newRules("")
+	if err != nil {
+		return "", "", err
+	}
+	r2, err := newRules(*wild)
+	if err != nil {
+		return "", "", err
+	}
+
+	hg := make(handGroups)
+	for lines.Next() {
+		l := lines.Line()
+		h, err := parseHand(l.Num, l.Text)
+		if err != nil {
+			return "", "", err
+		}
+		hg.add(h)
+	}
+	if err := lines.Err(); err != nil {
+		return "", "", err
+	}
+	logger.Infof("%d distinct hands", len(hg))
+
+	score1 := hg.winnings(r1)
+	logger.Infof("Part 1 score: %d", score1)
+	score2 := hg.winnings(r2)
+	logger.Infof("Part 2 score: %d", score2)
+	return strconv.Itoa(score1), strconv.Itoa(score2), nil
+}
@@ -0,0 +1,85 @@
+package day07
+
+import (
+	"aoc23/lib/daytest"
+	"testing"
+)
+
+func TestAnswers(t *testing.T) {
+	daytest.Answers(t, 7)
+}
+
+func BenchmarkParse(b *testing.B) { daytest.Bench(b, 7, "parse") }
+func BenchmarkPart1(b *testing.B) { daytest.Bench(b, 7, "part1") }
+func BenchmarkPart2(b *testing.B) { daytest.Bench(b, 7, "part2") }
+
+func TestHandString(t *testing.T) {
+	r, err := newRules("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		h    *hand
+		want string
+	}{
+		{&hand{cards: "32T3K", bid: 765}, "{32T3K  765}"},
+		{r.deal("32T3K", 765), "{32T3K  765 one-pair}"},
+		{r.deal("KK677", 28), "{KK677   28 two-pair}"},
+	}
+	for _, tc := range tests {
+		if got := tc.h.String(); got != tc.want {
+			t.Errorf("String() = %q, want %q", got, tc.want)
+		}
+	}
+}
+
+// BenchmarkRank10M ranks ten million dealt hands, as both parts do.
+func BenchmarkRank10M(b *testing.B) {
+	hands, err := parse(generate(10_000_000, 1))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := totalWinnings(hands, *wild); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestWinningsRepeatable(t *testing.T) {
+	hands, err := parse([]string{
+		"JJ234 10",
+		"QQ234 200",
+		"QJ234 3000",
+		"23456 1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := totalWinnings(hands, "JQ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 50; i++ {
+		if got, _ := totalWinnings(hands, "JQ"); got != want {
+			t.Fatalf("run %d: totalWinnings = %d, want %d as on the first run", i+2, got, want)
+		}
+	}
+}
+
+func TestWildOrder(t *testing.T) {
+	r, err := newRules("JQ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Weakest first: wild cards rank below the rest, J below Q as in -order.
+	hands := []string{"JJ234", "JQ234", "QJ234", "QQ234", "22234", "22342"}
+	for i := 1; i < len(hands); i++ {
+		a, b := r.deal(hands[i-1], 0), r.deal(hands[i], 0)
+		if c := compareHands(a, b); c != -1 {
+			t.Errorf("compareHands(%s, %s) = %d, want -1", a, b, c)
+		}
+	}
+}
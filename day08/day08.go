@@ -0,0 +1,634 @@
+package day08
+
+import (
+	"aoc23/lib"
+	"aoc23/lib/numtheory"
+	pc "aoc23/lib/parse"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	logger = lib.NewLogger("day08")
+
+	flags        = flag.NewFlagSet("day08", flag.ContinueOnError)
+	doPart2Naive = flags.Bool("naive", false, "calculate part 2 by walking all ghosts in lockstep (slow)")
+	vizFile      = flags.String("viz", "", "write a graphviz rendering of the (part 2) paths to this file")
+	doAnalyze    = flags.Bool("analyze", false, "write an analysis of the (part 2) network to stderr: reachable nodes, components and dead ends")
+	doGhosts     = flags.Bool("ghosts", false, "write each (part 2) ghost's prefix length, period and end node steps to stderr")
+
+	start1 = newMatcher("name:AAA")
+	end1   = newMatcher("name:ZZZ")
+	start2 = newMatcher("suffix:A")
+	end2   = newMatcher("suffix:Z")
+)
+
+func init() {
+	const usage = ": name:N[,N...], suffix:S or regex:RE"
+	flags.Var(start1, "start1", "part 1 start node"+usage)
+	flags.Var(end1, "end1", "part 1 end nodes"+usage)
+	flags.Var(start2, "start2", "part 2 start nodes"+usage)
+	flags.Var(end2, "end2", "part 2 end nodes"+usage)
+}
+
+// matcher picks out nodes by name. It is set from a spec: "name:AAA,BBB" for
+// exact names, "suffix:Z", or "regex:^..Z$". A spec with no kind is a list of
+// names.
+type matcher struct {
+	spec  string
+	match func(name string) bool
+}
+
+func newMatcher(spec string) *matcher {
+	m := &matcher{}
+	if err := m.Set(spec); err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func (m *matcher) String() string {
+	return m.spec
+}
+
+func (m *matcher) Set(spec string) error {
+	kind, arg, ok := strings.Cut(spec, ":")
+	if !ok {
+		kind, arg = "name", spec
+	}
+	switch kind {
+	case "name":
+		names := make(map[string]bool)
+		for _, n := range strings.Split(arg, ",") {
+			names[strings.TrimSpace(n)] = true
+		}
+		m.match = func(s string) bool { return names[s] }
+	case "suffix":
+		m.match = func(s string) bool { return strings.HasSuffix(s, arg) }
+	case "regex":
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return err
+		}
+		m.match = re.MatchString
+	default:
+		return fmt.Errorf("unknown node matcher %q: want name, suffix or regex", kind)
+	}
+	m.spec = spec
+	return nil
+}
+
+// all returns the names of the matching nodes, sorted.
+func (m *matcher) all(nodes map[string]*node) []string {
+	var res []string
+	for name := range nodes {
+		if m.match(name) {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+type turnDir int
+
+const (
+	turnLeft  turnDir = 0
+	turnRight turnDir = 1
+)
+
+func (td turnDir) String() string {
+	if td == turnLeft {
+		return "L"
+	}
+	return "R"
+}
+
+type node struct {
+	name  string
+	child []string
+}
+
+func part2Naive(turns []turnDir, nodes map[string]*node) int {
+	startTime := time.Now()
+	steps := 0
+	curNodeNames := start2.all(nodes)
+	logger.Infof("Progress: %10d steps. Current nodes: %v", steps, curNodeNames)
+
+	// Walk all nodes
+	for steps = 1; ; steps++ {
+		nextTurn := turns[(steps-1)%len(turns)]
+		logger.Tracef("Step %d", steps)
+		for i, n := range curNodeNames {
+			nextNodeName := nodes[n].child[nextTurn]
+			logger.Tracef("\t%s turn %s => %s", nextTurn, n, nextNodeName)
+			curNodeNames[i] = nextNodeName
+		}
+
+		if steps%1_000_000 == 0 {
+			logger.Infof("Progress: %10d steps. Current nodes: %v; %5s elapsed", steps, curNodeNames, time.Since(startTime).Round(time.Second))
+		}
+
+		// check end condition
+		if func() bool {
+			for _, n := range curNodeNames {
+				if !end2.match(n) {
+					return false
+				}
+			}
+			return true
+		}() {
+			break
+		}
+	}
+	logger.Infof("Final nodes after %d steps: %v. Elapsed time %s", steps, curNodeNames, time.Since(startTime))
+	return steps
+}
+
+// ghost is the path of one ghost. After walking prefix steps it is in a
+// cycle, repeating the same period steps forever.
+type ghost struct {
+	start  string
+	prefix int
+	period int
+	ends   []int // steps at which it stands on an end node, below prefix+period
+}
+
+// trace follows a ghost from start until its (node, turn) state repeats.
+func trace(start string, isEnd func(string) bool, turns []turnDir, nodes map[string]*node) ghost {
+	type state struct {
+		node string
+		turn int
+	}
+	seen := make(map[state]int)
+	g := ghost{start: start}
+	cur := start
+	for step := 0; ; step++ {
+		st := state{cur, step % len(turns)}
+		if first, ok := seen[st]; ok {
+			g.prefix, g.period = first, step-first
+			return g
+		}
+		seen[st] = step
+		if isEnd(cur) {
+			g.ends = append(g.ends, step)
+		}
+		next := nodes[cur].child[turns[st.turn]]
+		logger.Tracef("Stepping %s from %s -> %s", turns[st.turn], cur, next)
+		cur = next
+	}
+}
+
+// onEnd reports whether the ghost stands on an end node after step steps.
+func (g ghost) onEnd(step int) bool {
+	if step >= g.prefix {
+		step = g.prefix + (step-g.prefix)%g.period
+	}
+	i := sort.SearchInts(g.ends, step)
+	return i < len(g.ends) && g.ends[i] == step
+}
+
+// meet returns the first step at which every ghost stands on an end node.
+func meet(ghosts []ghost) (*big.Int, error) {
+	// Until the last ghost enters its cycle, they can only meet where that
+	// ghost passes an end node on its way in.
+	last := ghosts[0]
+	for _, g := range ghosts {
+		if len(g.ends) == 0 {
+			return nil, fmt.Errorf("ghost from %s never reaches an end node", g.start)
+		}
+		if g.prefix > last.prefix {
+			last = g
+		}
+	}
+	for _, e := range last.ends {
+		if e >= last.prefix {
+			break
+		}
+		all := true
+		for _, g := range ghosts {
+			all = all && g.onEnd(e)
+		}
+		if all {
+			return big.NewInt(int64(e)), nil
+		}
+	}
+
+	// After that, each ghost is on an end node at step T if T = e (mod
+	// period) for one of the ends e in its cycle. Solve for every choice of
+	// ends, carrying the congruences found so far.
+	type congruence struct{ r, m *big.Int }
+	sols := []congruence{{big.NewInt(0), big.NewInt(1)}}
+	for _, g := range ghosts {
+		var next []congruence
+		for _, s := range sols {
+			for _, e := range g.ends {
+				if e < g.prefix {
+					continue
+				}
+				r, m, err := numtheory.BigCRT(
+					[]*big.Int{s.r, big.NewInt(int64(e))},
+					[]*big.Int{s.m, big.NewInt(int64(g.period))},
+				)
+				if err == nil {
+					next = append(next, congruence{r, m})
+				}
+			}
+		}
+		sols = next
+	}
+	if len(sols) == 0 {
+		return nil, fmt.Errorf("ghosts never all stand on end nodes at once: their cycles never line up")
+	}
+
+	// Take the earliest solution once every ghost is in its cycle.
+	var best *big.Int
+	from := big.NewInt(int64(last.prefix))
+	for _, s := range sols {
+		t := new(big.Int).Set(s.r)
+		if t.Cmp(from) < 0 {
+			// Round up to the first t + k*m >= from.
+			k := new(big.Int).Sub(from, t)
+			k.Add(k, s.m).Sub(k, big.NewInt(1)).Quo(k, s.m)
+			t.Add(t, k.Mul(k, s.m))
+		}
+		if best == nil || t.Cmp(best) < 0 {
+			best = t
+		}
+	}
+	return best, nil
+}
+
+// traceGhosts traces the path of a ghost from every (part 2) start node.
+func traceGhosts(turns []turnDir, nodes map[string]*node) ([]ghost, error) {
+	startNodeNames := start2.all(nodes)
+	if len(startNodeNames) == 0 {
+		return nil, fmt.Errorf("no node matches start %v", start2)
+	}
+	logger.Debugf("Starting nodes: %v", startNodeNames)
+
+	ghosts := make([]ghost, len(startNodeNames))
+	for i, start := range startNodeNames {
+		ghosts[i] = trace(start, end2.match, turns, nodes)
+		g := ghosts[i]
+		logger.Infof("Ghost %s: prefix %d, period %d, end nodes at steps %v", g.start, g.prefix, g.period, g.ends)
+	}
+	return ghosts, nil
+}
+
+// writeGhosts lists each ghost's prefix, period and the steps at which it
+// stands on an end node.
+func writeGhosts(w io.Writer, ghosts []ghost) {
+	for _, g := range ghosts {
+		fmt.Fprintf(w, "Ghost %s: prefix %d, period %d, end nodes at steps %v\n", g.start, g.prefix, g.period, g.ends)
+	}
+}
+
+// part2Cycles finds when the ghosts meet by working out each ghost's cycle,
+// rather than walking them.
+func part2Cycles(turns []turnDir, nodes map[string]*node) (string, error) {
+	ghosts, err := traceGhosts(turns, nodes)
+	if err != nil {
+		return "", err
+	}
+
+	steps, err := meet(ghosts)
+	if err != nil {
+		return "", err
+	}
+	logger.Infof("Combined steps %v", steps)
+	return steps.String(), nil
+}
+
+func writeViz(w io.Writer, nodes map[string]*node) {
+	startTime := time.Now()
+	startNodeNames := start2.all(nodes)
+
+	fmt.Fprintf(w, "digraph AOC {\n")
+	fmt.Fprintf(w, "\tlayout=\"neato\";\n")
+	fmt.Fprintf(w, "\n")
+
+	for n := range nodes {
+		startstop := ""
+		if start2.match(n) {
+			startstop = ", color=\"green\""
+		} else if end2.match(n) {
+			startstop = ", color=\"red\""
+		} else {
+			continue
+		}
+		fmt.Fprintf(w, "\t\"%s\" [label=\"%s\"%s];\n", n, n, startstop)
+	}
+	fmt.Fprintf(w, "\n")
+
+	for _, startNode := range startNodeNames {
+		todo := []string{startNode}
+
+		seen := make(map[string]bool)
+		for len(todo) > 0 {
+			cur := todo[0]
+			todo = todo[1:]
+
+			if seen[cur] {
+				continue
+			}
+			seen[cur] = true
+
+			fmt.Fprintf(w, "\t\"%s\" -> \"%s\";\n", cur, nodes[cur].child[0])
+			fmt.Fprintf(w, "\t\"%s\" -> \"%s\";\n", cur, nodes[cur].child[1])
+			todo = append(todo, nodes[cur].child[0])
+			todo = append(todo, nodes[cur].child[1])
+		}
+	}
+
+	fmt.Fprintf(w, "}\n")
+	logger.Infof("Generated graph in %s", time.Since(startTime))
+}
+
+// reachable returns every node that can be reached from start by some
+// sequence of turns.
+func reachable(nodes map[string]*node, start string) map[string]bool {
+	seen := map[string]bool{start: true}
+	todo := []string{start}
+	for len(todo) > 0 {
+		cur := todo[len(todo)-1]
+		todo = todo[:len(todo)-1]
+		for _, c := range nodes[cur].child {
+			if !seen[c] {
+				seen[c] = true
+				todo = append(todo, c)
+			}
+		}
+	}
+	return seen
+}
+
+// components returns the strongly connected components of the network, each
+// sorted, in order of their first node.
+func components(nodes map[string]*node) [][]string {
+	// Tarjan's algorithm.
+	var (
+		res     [][]string
+		stack   []string
+		next    int
+		index   = make(map[string]int)
+		low     = make(map[string]int)
+		onStack = make(map[string]bool)
+		visit   func(n string)
+	)
+	visit = func(n string) {
+		index[n], low[n] = next, next
+		next++
+		stack = append(stack, n)
+		onStack[n] = true
+		for _, c := range nodes[n].child {
+			if _, ok := index[c]; !ok {
+				visit(c)
+				low[n] = lib.Min(low[n], low[c])
+			} else if onStack[c] {
+				low[n] = lib.Min(low[n], index[c])
+			}
+		}
+		if low[n] != index[n] {
+			return
+		}
+		var comp []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			comp = append(comp, top)
+			if top == n {
+				break
+			}
+		}
+		sort.Strings(comp)
+		res = append(res, comp)
+	}
+	for _, n := range lib.Keys(nodes) {
+		if _, ok := index[n]; !ok {
+			visit(n)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i][0] < res[j][0] })
+	return res
+}
+
+// writeAnalysis describes the (part 2) network, ignoring the order of turns:
+// what each start node can reach, which groups of nodes the ghosts can circle
+// in, and the dead ends from which no end node can be reached.
+func writeAnalysis(w io.Writer, nodes map[string]*node) {
+	starts, ends := start2.all(nodes), end2.all(nodes)
+	fmt.Fprintf(w, "%d nodes, %d start nodes (%v), %d end nodes (%v)\n", len(nodes), len(starts), start2, len(ends), end2)
+
+	fmt.Fprintf(w, "\nReachable from each start node:\n")
+	for _, s := range starts {
+		seen := reachable(nodes, s)
+		var hit []string
+		for _, e := range ends {
+			if seen[e] {
+				hit = append(hit, e)
+			}
+		}
+		fmt.Fprintf(w, "\t%s: %d nodes, end nodes %v\n", s, len(seen), hit)
+	}
+
+	comps := components(nodes)
+	fmt.Fprintf(w, "\n%d strongly connected components; those with cycles:\n", len(comps))
+	for _, comp := range comps {
+		n := nodes[comp[0]]
+		if len(comp) == 1 && n.child[0] != n.name && n.child[1] != n.name {
+			continue
+		}
+		var hit []string
+		for _, name := range comp {
+			if end2.match(name) {
+				hit = append(hit, name)
+			}
+		}
+		fmt.Fprintf(w, "\t%d nodes from %s, end nodes %v\n", len(comp), comp[0], hit)
+	}
+
+	// Walk back from the end nodes to find every node that can reach one.
+	parents := make(map[string][]string)
+	for _, n := range nodes {
+		for _, c := range n.child {
+			parents[c] = append(parents[c], n.name)
+		}
+	}
+	live := make(map[string]bool)
+	todo := append([]string(nil), ends...)
+	for _, e := range ends {
+		live[e] = true
+	}
+	for len(todo) > 0 {
+		cur := todo[len(todo)-1]
+		todo = todo[:len(todo)-1]
+		for _, p := range parents[cur] {
+			if !live[p] {
+				live[p] = true
+				todo = append(todo, p)
+			}
+		}
+	}
+	var dead []string
+	for _, name := range lib.Keys(nodes) {
+		if !live[name] {
+			dead = append(dead, name)
+		}
+	}
+	fmt.Fprintf(w, "\n%d dead ends, which reach no end node: %v\n", len(dead), dead)
+}
+
+type network struct {
+	turns []turnDir
+	nodes map[string]*node
+}
+
+func init() {
+	lib.Register(lib.Day{
+		Number: 8,
+		Parse:  lib.ParseFunc(parse),
+		Part1:  lib.PartFunc(part1),
+		Part2:  lib.PartFunc(part2),
+		Report: lib.ReportFunc(report),
+		Flags:  flags,
+	})
+}
+
+// nodeDef is a node definition as read, with positions for validation.
+type nodeDef struct {
+	name, left, right pc.Located[string]
+}
+
+// grammar is the layout of the network: a line of turns, then a block of
+// "<name> = (<left>, <right>)" nodes.
+var grammar = func() pc.Parser[rawNetwork] {
+	turn := pc.Alt(pc.Value(pc.Literal("L"), turnLeft), pc.Value(pc.Literal("R"), turnRight))
+	name := pc.At(pc.Word())
+	def := pc.Seq3(
+		name,
+		pc.Right(pc.Literal(" = ("), name),
+		pc.Left(pc.Right(pc.Literal(", "), name), pc.Literal(")")),
+		func(name, left, right pc.Located[string]) nodeDef {
+			return nodeDef{name, left, right}
+		},
+	)
+	return pc.Seq2(
+		pc.Left(pc.Many1(turn), pc.Many1(pc.Newline())),
+		pc.Left(pc.Many(pc.Left(def, pc.LineEnd())), pc.Many(pc.Newline())),
+		func(turns []turnDir, defs []nodeDef) rawNetwork {
+			return rawNetwork{turns, defs}
+		},
+	)
+}()
+
+// rawNetwork is a network as read, before validation.
+type rawNetwork struct {
+	turns []turnDir
+	defs  []nodeDef
+}
+
+// parse reads the network, checking that every node is defined exactly once.
+func parse(lines []string) (*network, error) {
+	raw, err := pc.Run(grammar, strings.Join(lines, ""))
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	turns := raw.turns
+	nodes := make(map[string]*node, len(raw.defs))
+	defined := make(map[string]lib.Pos)
+	for _, d := range raw.defs {
+		if prev, ok := defined[d.name.Value]; ok {
+			errs = append(errs, d.name.Pos.Errorf(d.name.Value, "node already defined on line %d", prev.Line))
+			continue
+		}
+		defined[d.name.Value] = d.name.Pos
+		nodes[d.name.Value] = &node{
+			name:  d.name.Value,
+			child: []string{d.left.Value, d.right.Value},
+		}
+	}
+	for _, d := range raw.defs {
+		for _, c := range []pc.Located[string]{d.left, d.right} {
+			if _, ok := nodes[c.Value]; !ok {
+				errs = append(errs, c.Pos.Errorf(c.Value, "undefined node"))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	for k, n := range nodes {
+		logger.Tracef("%s = %v", k, n)
+	}
+	logger.Debugf("Turns: %v", turns)
+
+	return &network{turns: turns, nodes: nodes}, nil
+}
+
+// report writes the -viz rendering, and the -analyze and -ghosts output.
+func report(net *network) error {
+	if *vizFile != "" {
+		fh, err := os.Create(*vizFile)
+		if err != nil {
+			return err
+		}
+		defer fh.Close()
+		writeViz(fh, net.nodes)
+	}
+
+	if *doAnalyze {
+		writeAnalysis(os.Stderr, net.nodes)
+	}
+
+	if *doGhosts {
+		ghosts, err := traceGhosts(net.turns, net.nodes)
+		if err != nil {
+			return err
+		}
+		writeGhosts(os.Stderr, ghosts)
+	}
+	return nil
+}
+
+// part1 walks from AAA to ZZZ. Part 2 samples have no AAA node.
+func part1(net *network) (string, error) {
+	starts := start1.all(net.nodes)
+	switch len(starts) {
+	case 0:
+		logger.Infof("No %v node; skipping part 1", start1)
+		return "", nil
+	case 1:
+	default:
+		return "", fmt.Errorf("part 1 needs a single start node, but %v matches %v", start1, starts)
+	}
+	g := trace(starts[0], end1.match, net.turns, net.nodes)
+	if len(g.ends) == 0 {
+		return "", fmt.Errorf("no %v node is ever reached from %s", end1, starts[0])
+	}
+	steps := g.ends[0]
+	logger.Infof("Went %s -> %v in %d steps", starts[0], end1, steps)
+	return strconv.Itoa(steps), nil
+}
+
+// part2 walks from every start node (**A) until all ghosts are on an end node
+// (**Z) at once.
+func part2(net *network) (string, error) {
+	if *doPart2Naive {
+		return strconv.Itoa(part2Naive(net.turns, net.nodes)), nil
+	}
+	return part2Cycles(net.turns, net.nodes)
+}
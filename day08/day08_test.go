@@ -0,0 +1,126 @@
+package day08
+
+import (
+	"aoc23/lib"
+	"aoc23/lib/daytest"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestAnswers(t *testing.T) {
+	daytest.Answers(t, 8)
+}
+
+func BenchmarkParse(b *testing.B) { daytest.Bench(b, 8, "parse") }
+func BenchmarkPart1(b *testing.B) { daytest.Bench(b, 8, "part1") }
+func BenchmarkPart2(b *testing.B) { daytest.Bench(b, 8, "part2") }
+
+// walk steps ghosts from starts in lockstep, and returns the first step at
+// which all stand on end nodes, or -1 if none does within limit steps.
+func walk(starts []string, isEnd func(string) bool, turns []turnDir, nodes map[string]*node, limit int) int {
+	cur := append([]string(nil), starts...)
+	for step := 0; step <= limit; step++ {
+		all := true
+		for _, n := range cur {
+			all = all && isEnd(n)
+		}
+		if all {
+			return step
+		}
+		for i, n := range cur {
+			cur[i] = nodes[n].child[turns[step%len(turns)]]
+		}
+	}
+	return -1
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// TestMeet checks meet against walking the ghosts in lockstep, on small
+// random networks.
+func TestMeet(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var early, multiEnds, nonCoprime, never int
+	for trial := 0; trial < 3000; trial++ {
+		nodes := make(map[string]*node)
+		names := make([]string, 2+rng.Intn(7))
+		for i := range names {
+			names[i] = fmt.Sprintf("N%d", i)
+		}
+		ends := make(map[string]bool)
+		for _, n := range names {
+			nodes[n] = &node{name: n, child: []string{names[rng.Intn(len(names))], names[rng.Intn(len(names))]}}
+			ends[n] = rng.Intn(3) == 0
+		}
+		isEnd := func(n string) bool { return ends[n] }
+		turns := make([]turnDir, 1+rng.Intn(3))
+		for i := range turns {
+			turns[i] = turnDir(rng.Intn(2))
+		}
+		starts := make([]string, 1+rng.Intn(3))
+		ghosts := make([]ghost, len(starts))
+		maxPrefix, limit := 0, 1
+		for i := range starts {
+			starts[i] = names[rng.Intn(len(names))]
+			ghosts[i] = trace(starts[i], isEnd, turns, nodes)
+			maxPrefix = lib.Max(maxPrefix, ghosts[i].prefix)
+			limit *= ghosts[i].period
+		}
+		// If the ghosts ever meet, they meet within one combined cycle of
+		// the last one entering its cycle.
+		limit += maxPrefix
+
+		want := walk(starts, isEnd, turns, nodes, limit)
+		got, err := meet(ghosts)
+		if want < 0 {
+			if err == nil {
+				t.Fatalf("trial %d: meet(%v) = %v, want an error", trial, ghosts, got)
+			}
+			never++
+			continue
+		}
+		if err != nil || got.Int64() != int64(want) {
+			t.Fatalf("trial %d: meet(%v) = %v, %v, want %d", trial, ghosts, got, err, want)
+		}
+
+		if want < maxPrefix {
+			early++
+		}
+		for i, g := range ghosts {
+			inCycle := 0
+			for _, e := range g.ends {
+				if e >= g.prefix {
+					inCycle++
+				}
+			}
+			if inCycle > 1 {
+				multiEnds++
+			}
+			for _, h := range ghosts[:i] {
+				if g.period > 1 && h.period > 1 && g.period != h.period && gcd(g.period, h.period) > 1 {
+					nonCoprime++
+				}
+			}
+		}
+	}
+	// Make sure the random networks covered the interesting cases.
+	for _, c := range []struct {
+		name string
+		n    int
+	}{
+		{"meetings before every ghost is in its cycle", early},
+		{"ghosts with several end nodes per cycle", multiEnds},
+		{"ghosts with periods sharing a factor", nonCoprime},
+		{"ghosts that never meet", never},
+	} {
+		if c.n == 0 {
+			t.Errorf("no trial had %s", c.name)
+		}
+	}
+}
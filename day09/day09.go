@@ -0,0 +1,88 @@
+package day09
+
+import (
+	"aoc23/lib"
+	"aoc23/lib/sequence"
+	"flag"
+	"fmt"
+	"math/big"
+)
+
+var (
+	logger = lib.NewLogger("day09")
+
+	flags  = flag.NewFlagSet("day09", flag.ContinueOnError)
+	termAt *big.Int
+)
+
+func init() {
+	flags.Func("term", "log term N of each history, counting from 0 (N may be negative, or huge)", func(s string) error {
+		n, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return fmt.Errorf("invalid term %q", s)
+		}
+		termAt = n
+		return nil
+	})
+}
+
+// history is a sequence of readings, and the polynomial that produces them.
+type history struct {
+	terms []int
+	poly  *sequence.Poly
+}
+
+func init() {
+	lib.Register(lib.Day{
+		Number: 9,
+		Parse:  lib.ParseFunc(parse),
+		Part1:  lib.PartFunc(part1),
+		Part2:  lib.PartFunc(part2),
+		Flags:  flags,
+	})
+}
+
+func parse(lines []string) ([]history, error) {
+	var histories []history
+	for linen, line := range lines {
+		pos := lib.Pos{Line: linen + 1}
+		terms, err := lib.ParseInts(pos, line)
+		if err != nil {
+			return nil, err
+		}
+		poly, err := sequence.Fit(terms)
+		if err != nil {
+			return nil, pos.Errorf("", "%w", err)
+		}
+		logger.Debugf("History %d: degree %d, %v", linen+1, poly.Degree(), poly)
+		if termAt != nil {
+			logger.Infof("History %d: term %v is %v", linen+1, termAt, poly.AtBig(termAt))
+		}
+		histories = append(histories, history{terms, poly})
+	}
+	return histories, nil
+}
+
+// part1 sums the next reading of every history.
+func part1(histories []history) (string, error) {
+	sum := new(big.Int)
+	for i, h := range histories {
+		e := h.poly.At(len(h.terms))
+		logger.Debugf("Seq[%2d] %v => %v", i, h.terms, e)
+		sum.Add(sum, e)
+	}
+	logger.Infof("Part 1 sum: %v", sum)
+	return sum.String(), nil
+}
+
+// part2 sums the reading before the first of every history.
+func part2(histories []history) (string, error) {
+	sum := new(big.Int)
+	for i, h := range histories {
+		e := h.poly.At(-1)
+		logger.Debugf("Seq[%2d] %v => %v", i, h.terms, e)
+		sum.Add(sum, e)
+	}
+	logger.Infof("Part 2 sum: %v", sum)
+	return sum.String(), nil
+}
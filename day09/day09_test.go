@@ -0,0 +1,14 @@
+package day09
+
+import (
+	"aoc23/lib/daytest"
+	"testing"
+)
+
+func TestAnswers(t *testing.T) {
+	daytest.Answers(t, 9)
+}
+
+func BenchmarkParse(b *testing.B) { daytest.Bench(b, 9, "parse") }
+func BenchmarkPart1(b *testing.B) { daytest.Bench(b, 9, "part1") }
+func BenchmarkPart2(b *testing.B) { daytest.Bench(b, 9, "part2") }
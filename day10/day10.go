@@ -0,0 +1,349 @@
+package day10
+
+import (
+	"aoc23/lib"
+	"aoc23/lib/grid"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var (
+	logger = lib.NewLogger("day10")
+
+	flags   = flag.NewFlagSet("day10", flag.ContinueOnError)
+	svgFile = flags.String("svg", "", "write a SVG rendering of the main path to this file")
+)
+
+// pipeDirs lists the directions each pipe connects to.
+var pipeDirs = map[rune][]grid.Dir{
+	'|': {grid.North, grid.South},
+	'-': {grid.West, grid.East},
+	'F': {grid.East, grid.South},
+	'7': {grid.West, grid.South},
+	'L': {grid.East, grid.North},
+	'J': {grid.West, grid.North},
+}
+
+func isCorner(pipe rune) bool {
+	switch pipe {
+	case 'F', '7', 'L', 'J':
+		return true
+	}
+	return false
+}
+
+type pipeMap struct {
+	grid     *grid.Grid[rune] // represents the full grid and pipe layout.
+	dist     *grid.Grid[int]  // shortest distance to the starting position along the path.
+	startPos grid.Point       // starting position.
+	groups   *grid.Grid[int]  // what connected group each cell is a part of.
+	corners  []grid.Point     // corners along the path, with corners[0] == startPos.
+}
+
+func (pm *pipeMap) renderGrid() string {
+	return pm.grid.Render(func(p grid.Point, pipe rune) string {
+		if p == pm.startPos {
+			return fmt.Sprintf("\x1b[32m%2c\x1b[0m", pipe)
+		}
+		return fmt.Sprintf("%2c", pipe)
+	})
+}
+
+func (pm *pipeMap) renderGroups() string {
+	return pm.grid.Render(func(p grid.Point, pipe rune) string {
+		if p == pm.startPos {
+			return fmt.Sprintf("\x1b[32m%2c\x1b[0m", pipe)
+		} else if pm.groups.At(p) > 0 {
+			return fmt.Sprintf("\x1b[35m%2c\x1b[0m", pipe)
+		}
+		return fmt.Sprintf("%2c", pipe)
+	})
+}
+
+func (pm *pipeMap) renderDist() string {
+	return pm.dist.Render(func(p grid.Point, dist int) string {
+		if pm.grid.At(p) == '.' {
+			return " ."
+		} else if dist > 0 {
+			return fmt.Sprintf("\x1b[32m%2s\x1b[0m", lib.Base10ToBase62(dist))
+		}
+		return fmt.Sprintf("%2s", lib.Base10ToBase62(dist))
+	})
+}
+
+func (pm *pipeMap) renderSVG() string {
+	maxX := 0
+	maxY := 0
+	for _, pt := range pm.corners {
+		if pt.X > maxX {
+			maxX = pt.X
+		}
+		if pt.Y > maxY {
+			maxY = pt.Y
+		}
+	}
+	maxX *= 10
+	maxY *= 10
+
+	var sb strings.Builder
+	sb.WriteString("<html><body>\n")
+	fmt.Fprintf(&sb, "<svg height=\"%d\" width=\"%d\">\n", maxX+50, maxY+50)
+
+	// Draw with a polygon.
+	fmt.Fprintf(&sb, "  <polygon style=\"fill:rgb(0,128,64);stroke:rgb(0,0,0);stroke-width:2\" points=\"")
+	for _, pt := range pm.corners {
+		fmt.Fprintf(&sb, "%d,%d ", pt.X*10, pt.Y*10)
+	}
+	fmt.Fprintf(&sb, "\"></polygon>\n")
+
+	// circle starting position
+	fmt.Fprintf(&sb, "  <circle cx=\"%d\" cy=\"%d\" r=\"%d\" style=\"stroke:rgb(0,0,0);stroke-width:2;fill:rgba(0,255,64,0.8)\"></circle>\n",
+		pm.corners[0].X*10, pm.corners[0].Y*10, 5,
+	)
+
+	sb.WriteString("</svg>\n")
+	sb.WriteString("</body></html>\n")
+
+	return sb.String()
+}
+
+func (pm *pipeMap) findCorners() {
+	var innerDFS func(p grid.Point, seen *grid.Grid[bool])
+	innerDFS = func(p grid.Point, seen *grid.Grid[bool]) {
+		if seen.At(p) {
+			return
+		}
+		seen.Set(p, true)
+
+		curPipe := pm.grid.At(p)
+		if isCorner(curPipe) {
+			pm.corners = append(pm.corners, p)
+		}
+		for _, d := range pipeDirs[curPipe] {
+			if next := p.Add(d); pm.grid.InBounds(next) {
+				innerDFS(next, seen)
+			}
+		}
+	}
+
+	seen := grid.New[bool](pm.grid.Width(), pm.grid.Height())
+	innerDFS(pm.startPos, seen)
+}
+
+func (pm *pipeMap) dfsDistance(start grid.Point) {
+	logger.Debugf("Starting DFS at %v -> %c", start, pm.grid.At(start))
+
+	var innerDFS func(p grid.Point, curDist int, seen *grid.Grid[bool])
+	innerDFS = func(p grid.Point, curDist int, seen *grid.Grid[bool]) {
+		if seen.At(p) {
+			if curDist >= pm.dist.At(p) {
+				return
+			}
+			// We've seen it, but on a longer path. Re-process this
+			// location with the shorter path.
+		}
+		seen.Set(p, true)
+		pm.dist.Set(p, curDist)
+
+		// Ground ('.') has no pipe, so goes nowhere.
+		for _, d := range pipeDirs[pm.grid.At(p)] {
+			if next := p.Add(d); pm.grid.InBounds(next) {
+				innerDFS(next, curDist+1, seen)
+			}
+		}
+	}
+
+	seen := grid.New[bool](pm.grid.Width(), pm.grid.Height())
+	innerDFS(start, 0, seen)
+}
+
+func (pm *pipeMap) replaceStartPos() error {
+	// Inspect adjacent elements: S connects towards each neighbour that
+	// flows back into it.
+	okay := make(map[grid.Dir]bool)
+	for _, d := range grid.Dirs4 {
+		for _, nd := range pipeDirs[pm.grid.At(pm.startPos.Add(d))] {
+			if nd == d.Reverse() {
+				okay[d] = true
+			}
+		}
+	}
+
+	for _, pipe := range "-|LJF7" {
+		dirs := pipeDirs[pipe]
+		if okay[dirs[0]] && okay[dirs[1]] {
+			pm.grid.Set(pm.startPos, pipe)
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot infer what S should be replaced with")
+}
+
+func (pm *pipeMap) findGroups() {
+	isInside := func(p grid.Point) bool {
+		x, y := p.X, p.Y
+		prevPt := pm.corners[len(pm.corners)-1]
+		c := false
+		for _, pt := range pm.corners {
+			lineX1 := lib.Min(pt.X, prevPt.X)
+			lineX2 := lib.Max(pt.X, prevPt.X)
+			lineY1 := lib.Min(pt.Y, prevPt.Y)
+			lineY2 := lib.Min(pt.Y, prevPt.Y)
+
+			if (x == pt.X) && (y == pt.Y) {
+				// corner of line, consider that outside.
+				return false
+			} else if y == pt.Y && x >= lineX1 && x <= lineX2 {
+				// on line, consider that outside.
+				return false
+			} else if x == pt.X && y >= lineY1 && y <= lineY2 {
+				// on line, consider that outside.
+				return false
+			} else if (pt.Y > y) != (prevPt.Y > y) {
+				// our (x,y)-point crosses the line.
+				slope := (x-pt.X)*(prevPt.Y-pt.Y) -
+					(y-pt.Y)*(prevPt.X-pt.X)
+				if slope == 0 {
+					// on line.
+					return false
+				}
+				if (slope < 0) != (prevPt.Y < pt.Y) {
+					c = !c
+				}
+			}
+
+			prevPt = pt
+		}
+		return c
+	}
+
+	pm.grid.Each(func(p grid.Point, _ rune) {
+		if isInside(p) {
+			pm.groups.Set(p, 1)
+		}
+	})
+}
+
+func (pm *pipeMap) enclosedArea() int {
+	var helper func(p grid.Point, seen *grid.Grid[bool]) int
+	helper = func(p grid.Point, seen *grid.Grid[bool]) int {
+		if seen.At(p) {
+			return 0
+		}
+		seen.Set(p, true)
+
+		mySize := 1
+		if pm.dist.At(p) > 0 || pm.groups.At(p) == 0 {
+			// it's on or outside the path.
+			return 0
+		}
+
+		for _, next := range pm.grid.Neighbors8(p) {
+			mySize += helper(next, seen)
+		}
+
+		return mySize
+	}
+
+	seen := grid.New[bool](pm.grid.Width(), pm.grid.Height())
+	sum := 0
+	pm.grid.Each(func(p grid.Point, _ rune) {
+		sum += helper(p, seen)
+	})
+	return sum
+}
+
+func init() {
+	lib.Register(lib.Day{
+		Number: 10,
+		Parse:  lib.ParseFunc(parse),
+		Part1:  lib.PartFunc(part1),
+		Part2:  lib.PartFunc(part2),
+		Report: lib.ReportFunc(report),
+		Flags:  flags,
+	})
+}
+
+func parse(lines []string) (*pipeMap, error) {
+	g, err := grid.Runes(lines)
+	if err != nil {
+		return nil, err
+	}
+	pipes := &pipeMap{
+		grid:   g,
+		dist:   grid.New[int](g.Width(), g.Height()),
+		groups: grid.New[int](g.Width(), g.Height()),
+	}
+
+	var unknown error
+	g.Each(func(p grid.Point, r rune) {
+		if _, ok := pipeDirs[r]; !ok && r != '.' && r != 'S' && unknown == nil {
+			unknown = lib.Pos{Line: p.Y + 1, Column: p.X + 1}.Errorf(string(r), "unknown grid element")
+		}
+	})
+	if unknown != nil {
+		return nil, unknown
+	}
+	starts := g.FindAll(func(r rune) bool { return r == 'S' })
+	if len(starts) != 1 {
+		return nil, fmt.Errorf("invalid starting position / starting position not specified")
+	}
+	pipes.startPos = starts[0]
+	if err := pipes.replaceStartPos(); err != nil {
+		return nil, err
+	}
+	pipes.findCorners()
+
+	logger.Debugf("Corners:")
+	for i, corn := range pipes.corners {
+		logger.Debugf("  [%2d] %v", i, corn)
+	}
+	if logger.Enabled(lib.LevelDebug) {
+		logger.Debugf("Grid:\n%s", pipes.renderGrid())
+	}
+	return pipes, nil
+}
+
+// report writes the SVG rendering of the main path, if -svg is set.
+func report(pipes *pipeMap) error {
+	if *svgFile == "" {
+		return nil
+	}
+	return os.WriteFile(*svgFile, []byte(pipes.renderSVG()), 0644)
+}
+
+// part1 finds the point on the loop furthest from the start.
+func part1(pipes *pipeMap) (string, error) {
+	pipes.dfsDistance(pipes.startPos)
+	if logger.Enabled(lib.LevelDebug) {
+		logger.Debugf("Dist:\n%s", pipes.renderDist())
+	}
+
+	max := 0
+	var maxLoc grid.Point
+	pipes.dist.Each(func(p grid.Point, d int) {
+		if d > max {
+			max = d
+			maxLoc = p
+		}
+	})
+	logger.Infof("Max distance is at %v = %d", maxLoc, max)
+	return strconv.Itoa(max), nil
+}
+
+// part2 finds the area enclosed by the loop.
+func part2(pipes *pipeMap) (string, error) {
+	// The distances mark which cells are on the path.
+	pipes.dfsDistance(pipes.startPos)
+
+	pipes.findGroups()
+	if logger.Enabled(lib.LevelDebug) {
+		logger.Debugf("Groups:\n%s", pipes.renderGroups())
+	}
+	area := pipes.enclosedArea()
+	logger.Infof("Enclosed area: %d", area)
+	return strconv.Itoa(area), nil
+}
@@ -0,0 +1,14 @@
+package day10
+
+import (
+	"aoc23/lib/daytest"
+	"testing"
+)
+
+func TestAnswers(t *testing.T) {
+	daytest.Answers(t, 10)
+}
+
+func BenchmarkParse(b *testing.B) { daytest.Bench(b, 10, "parse") }
+func BenchmarkPart1(b *testing.B) { daytest.Bench(b, 10, "part1") }
+func BenchmarkPart2(b *testing.B) { daytest.Bench(b, 10, "part2") }
@@ -0,0 +1,137 @@
+package day11
+
+import (
+	"aoc23/lib"
+	"aoc23/lib/grid"
+	"flag"
+	"fmt"
+	"strconv"
+)
+
+var (
+	logger = lib.NewLogger("day11")
+
+	flags       = flag.NewFlagSet("day11", flag.ContinueOnError)
+	scaleFactor = flags.Int("scale", 1_000_000, "scale factor for part 2 (part 1 always uses 2)")
+)
+
+// hasGalaxy reports whether any of the cells holds a galaxy.
+func hasGalaxy(cells []int) bool {
+	for _, el := range cells {
+		if el > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	lib.Register(lib.Day{
+		Number: 11,
+		Parse:  lib.ParseFunc(parse),
+		Part1:  lib.PartFunc(part1),
+		Part2:  lib.PartFunc(part2),
+		Flags:  flags,
+	})
+}
+
+// parse loads the image, marking galaxies with their ID and the cells of empty
+// rows and columns with -1.
+func parse(lines []string) (*grid.Grid[int], error) {
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("empty input")
+	}
+
+	galaxyID := 1
+	image, err := grid.FromLines(lines, func(_ grid.Point, r rune) int {
+		if r != '#' {
+			return 0
+		}
+		galaxyID++
+		return galaxyID - 1
+	})
+	if err != nil {
+		return nil, err
+	}
+	for y := 0; y < image.Height(); y++ {
+		if !hasGalaxy(image.Row(y)) {
+			for x := 0; x < image.Width(); x++ {
+				image.Set(grid.Point{X: x, Y: y}, -1)
+			}
+		}
+	}
+	for x := 0; x < image.Width(); x++ {
+		if !hasGalaxy(image.Col(x)) {
+			for y := 0; y < image.Height(); y++ {
+				image.Set(grid.Point{X: x, Y: y}, -1)
+			}
+		}
+	}
+
+	if logger.Enabled(lib.LevelDebug) {
+		logger.Debugf("Grid:\n%s", image.Render(func(_ grid.Point, el int) string {
+			if el == 0 {
+				return "  ."
+			}
+			return fmt.Sprintf("%3d", el)
+		}))
+	}
+
+	return image, nil
+}
+
+func part1(image *grid.Grid[int]) (string, error) {
+	return strconv.Itoa(totalDistance(image, 2)), nil
+}
+
+func part2(image *grid.Grid[int]) (string, error) {
+	return strconv.Itoa(totalDistance(image, *scaleFactor)), nil
+}
+
+// totalDistance sums the distances between all galaxy pairs, with empty rows
+// and columns expanded by scaleFactor.
+func totalDistance(image *grid.Grid[int], scaleFactor int) int {
+	galaxies := make(map[int]grid.Point)
+	y := 0
+	for row := 0; row < image.Height(); row++ {
+		cells := image.Row(row)
+		if !hasGalaxy(cells) {
+			// Line is empty, expand by scale factor
+			y += scaleFactor
+		} else {
+			x := 0
+			for _, el := range cells {
+				if el > 0 {
+					// Add a galaxy, and move once.
+					galaxies[el] = grid.Point{X: x, Y: y}
+					x++
+				} else if el < 0 {
+					// Expand by moving scaleFactor.
+					x += scaleFactor
+				} else {
+					// Just move once.
+					x++
+				}
+			}
+			y++
+		}
+	}
+
+	logger.Debugf("Galaxies:")
+	keys := lib.Keys(galaxies)
+	for _, k := range keys {
+		g := galaxies[k]
+		logger.Debugf("  [%2d] %v", k, g)
+	}
+
+	sum := 0
+	for i, k1 := range keys {
+		for _, k2 := range keys[i+1:] {
+			d := galaxies[k1].ManhattanDist(galaxies[k2])
+			logger.Tracef("Distance %d -> %d = %d", k1, k2, d)
+			sum += d
+		}
+	}
+	logger.Infof("Total distance (scale %d): %d", scaleFactor, sum)
+	return sum
+}
@@ -0,0 +1,14 @@
+package day11
+
+import (
+	"aoc23/lib/daytest"
+	"testing"
+)
+
+func TestAnswers(t *testing.T) {
+	daytest.Answers(t, 11)
+}
+
+func BenchmarkParse(b *testing.B) { daytest.Bench(b, 11, "parse") }
+func BenchmarkPart1(b *testing.B) { daytest.Bench(b, 11, "part1") }
+func BenchmarkPart2(b *testing.B) { daytest.Bench(b, 11, "part2") }
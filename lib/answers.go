@@ -0,0 +1,78 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AnswerKey identifies an expected answer by input file name and part.
+type AnswerKey struct {
+	Input string
+	Part  int
+}
+
+// Answers holds the known-good answers for a day.
+type Answers map[AnswerKey]string
+
+// LoadAnswers reads an answers file. Each non-blank line holds an input file
+// name, a part number and the expected answer, separated by whitespace. Lines
+// starting with '#' are comments.
+func LoadAnswers(filename string) (Answers, error) {
+	it, err := GetInputFileIterator(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	answers := make(Answers)
+	for linen := 1; ; linen++ {
+		line, err := it.NextLine()
+		if errors.Is(err, io.EOF) {
+			return answers, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("%s:%d: expected \"<input> <part> <answer>\", got %q", filename, linen, line)
+		}
+		part, err := strconv.Atoi(parts[1])
+		if err != nil || part < 1 || part > 2 {
+			return nil, fmt.Errorf("%s:%d: invalid part %q", filename, linen, parts[1])
+		}
+		key := AnswerKey{Input: parts[0], Part: part}
+		if _, ok := answers[key]; ok {
+			return nil, fmt.Errorf("%s:%d: duplicate answer for %s part %d", filename, linen, key.Input, key.Part)
+		}
+		answers[key] = parts[2]
+	}
+}
+
+// InputFiles lists the inputs in dir that answers are checked against: every
+// input-sample*.txt, then input.txt if there is one.
+func InputFiles(dir string, samplesOnly bool) ([]string, error) {
+	inputs, err := filepath.Glob(filepath.Join(dir, "input-sample*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(inputs)
+	if samplesOnly {
+		return inputs, nil
+	}
+	filename := filepath.Join(dir, "input.txt")
+	if _, err := os.Stat(filename); err == nil {
+		inputs = append(inputs, filename)
+	}
+	return inputs, nil
+}
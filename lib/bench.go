@@ -0,0 +1,142 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BenchStages lists the stages timed by Day.Bench, in order.
+var BenchStages = []string{"parse", "part1", "part2"}
+
+// BenchKey identifies a benchmarked stage.
+type BenchKey struct {
+	Day   int
+	Stage string
+}
+
+// BenchResult holds the averaged cost of running one stage of a day.
+type BenchResult struct {
+	BenchKey
+	N           int // number of runs averaged
+	NsPerOp     int64
+	BytesPerOp  int64
+	AllocsPerOp int64
+}
+
+// Bench runs each stage of the day count times over lines. The parts are
+// given freshly parsed input on every run, and only the part itself is
+// measured.
+func (d *Day) Bench(lines []string, count int) ([]BenchResult, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("invalid bench count %d", count)
+	}
+
+	var res []BenchResult
+	for _, stage := range BenchStages {
+		br := BenchResult{
+			BenchKey: BenchKey{Day: d.Number, Stage: stage},
+			N:        count,
+		}
+		var elapsed time.Duration
+		var before, after runtime.MemStats
+		for i := 0; i < count; i++ {
+			var input any
+			if stage != "parse" {
+				var err error
+				if input, err = d.Parse(lines); err != nil {
+					return nil, fmt.Errorf("day %d: %v", d.Number, err)
+				}
+			}
+
+			runtime.ReadMemStats(&before)
+			startTime := time.Now()
+			var err error
+			switch stage {
+			case "parse":
+				_, err = d.Parse(lines)
+			case "part1":
+				_, err = d.Part1(input)
+			case "part2":
+				_, err = d.Part2(input)
+			}
+			elapsed += time.Since(startTime)
+			runtime.ReadMemStats(&after)
+			if err != nil {
+				return nil, fmt.Errorf("day %d %s: %v", d.Number, stage, err)
+			}
+
+			br.BytesPerOp += int64(after.TotalAlloc - before.TotalAlloc)
+			br.AllocsPerOp += int64(after.Mallocs - before.Mallocs)
+		}
+		br.NsPerOp = elapsed.Nanoseconds() / int64(count)
+		br.BytesPerOp /= int64(count)
+		br.AllocsPerOp /= int64(count)
+		res = append(res, br)
+	}
+	return res, nil
+}
+
+const benchHeader = "day\tstage\tn\tns_per_op\tbytes_per_op\tallocs_per_op"
+
+// WriteBenchResults writes results as a tab-separated baseline that can be
+// read back with LoadBenchResults.
+func WriteBenchResults(w io.Writer, results []BenchResult) error {
+	if _, err := fmt.Fprintln(w, benchHeader); err != nil {
+		return err
+	}
+	for _, br := range results {
+		_, err := fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%d\t%d\n",
+			br.Day, br.Stage, br.N, br.NsPerOp, br.BytesPerOp, br.AllocsPerOp)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadBenchResults reads a baseline written by WriteBenchResults.
+func LoadBenchResults(filename string) (map[BenchKey]BenchResult, error) {
+	it, err := GetInputFileIterator(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	res := make(map[BenchKey]BenchResult)
+	for linen := 1; ; linen++ {
+		line, err := it.NextLine()
+		if errors.Is(err, io.EOF) {
+			return res, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" || line == benchHeader {
+			continue
+		}
+		parts := strings.Split(line, "\t")
+		if len(parts) != 6 {
+			return nil, fmt.Errorf("%s:%d: expected 6 fields, got %d", filename, linen, len(parts))
+		}
+		var nums [5]int64
+		for i, s := range []string{parts[0], parts[2], parts[3], parts[4], parts[5]} {
+			if nums[i], err = strconv.ParseInt(s, 10, 64); err != nil {
+				return nil, fmt.Errorf("%s:%d: %v", filename, linen, err)
+			}
+		}
+		br := BenchResult{
+			BenchKey:    BenchKey{Day: int(nums[0]), Stage: parts[1]},
+			N:           int(nums[1]),
+			NsPerOp:     nums[2],
+			BytesPerOp:  nums[3],
+			AllocsPerOp: nums[4],
+		}
+		res[br.BenchKey] = br
+	}
+}
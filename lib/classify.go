@@ -1,7 +1,6 @@
 package lib
 
-import "unicode"
-
+// IsNum reports whether r is an ASCII digit.
 func IsNum(r rune) bool {
-	return unicode.IsDigit(r)
+	return r >= '0' && r <= '9'
 }
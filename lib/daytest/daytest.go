@@ -0,0 +1,96 @@
+// Package daytest checks registered days from go test, the same way aoc
+// verify does.
+package daytest
+
+import (
+	"aoc23/lib"
+	"path/filepath"
+	"testing"
+)
+
+// Answers runs day n over each of its inputs in the current directory, which
+// go test makes the day's own, and compares both parts with answers.txt.
+// Parts without a stored answer are only logged, unless they produced one.
+func Answers(t *testing.T, n int) {
+	t.Helper()
+	d, ok := lib.LookupDay(n)
+	if !ok {
+		t.Fatalf("day %d is not registered", n)
+	}
+	answers, err := lib.LoadAnswers("answers.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs, err := lib.InputFiles(".", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		t.Fatal("no inputs")
+	}
+
+	for _, filename := range inputs {
+		name := filepath.Base(filename)
+		t.Run(name, func(t *testing.T) {
+			lines, err := lib.GetInputFileAll(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got [2]string
+			got[0], got[1], err = d.Solve(lines)
+			if err != nil {
+				t.Fatal(lib.WithSource(err, filename))
+			}
+			for i := range got {
+				key := lib.AnswerKey{Input: name, Part: i + 1}
+				want, ok := answers[key]
+				switch {
+				case !ok && got[i] == "":
+					// part does not apply to this input
+				case !ok:
+					t.Logf("part %d: no stored answer (got %s)", key.Part, got[i])
+				case got[i] != want:
+					t.Errorf("part %d = %q, want %q", key.Part, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+// Bench times one stage of day n over its input.txt, as aoc bench does:
+// "parse", or "part1" or "part2" given freshly parsed input on each run.
+func Bench(b *testing.B, n int, stage string) {
+	d, ok := lib.LookupDay(n)
+	if !ok {
+		b.Fatalf("day %d is not registered", n)
+	}
+	lines, err := lib.GetInputFileAll("input.txt")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if stage == "parse" {
+			if _, err := d.Parse(lines); err != nil {
+				b.Fatal(err)
+			}
+			continue
+		}
+
+		b.StopTimer()
+		input, err := d.Parse(lines)
+		if err != nil {
+			b.Fatal(err)
+		}
+		part := d.Part1
+		if stage == "part2" {
+			part = d.Part2
+		}
+		b.StartTimer()
+		if _, err := part(input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
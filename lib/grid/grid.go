@@ -0,0 +1,301 @@
+// Package grid provides a generic fixed-size 2D grid, along with the point
+// and direction types used to walk it.
+package grid
+
+import (
+	"aoc23/lib"
+	"fmt"
+	"strings"
+)
+
+// Point is a cell position. X grows to the right and Y grows downwards, so
+// (0,0) is the top-left cell and Y matches the input line number.
+type Point struct {
+	X, Y int
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("(%d,%d)", p.X, p.Y)
+}
+
+// Add returns the point one step from p in direction d.
+func (p Point) Add(d Dir) Point {
+	return Point{p.X + d.DX, p.Y + d.DY}
+}
+
+// ManhattanDist returns the taxicab distance between p and q.
+func (p Point) ManhattanDist(q Point) int {
+	return lib.Abs(p.X-q.X) + lib.Abs(p.Y-q.Y)
+}
+
+// Dir is a single step between adjacent cells.
+type Dir struct {
+	DX, DY int
+}
+
+var (
+	North     = Dir{0, -1}
+	South     = Dir{0, +1}
+	East      = Dir{+1, 0}
+	West      = Dir{-1, 0}
+	NorthEast = Dir{+1, -1}
+	SouthEast = Dir{+1, +1}
+	SouthWest = Dir{-1, +1}
+	NorthWest = Dir{-1, -1}
+)
+
+// Dirs4 are the orthogonal directions, clockwise from North.
+var Dirs4 = []Dir{North, East, South, West}
+
+// Dirs8 are the orthogonal and diagonal directions, clockwise from North.
+var Dirs8 = []Dir{North, NorthEast, East, SouthEast, South, SouthWest, West, NorthWest}
+
+// Reverse returns the opposite direction.
+func (d Dir) Reverse() Dir {
+	return Dir{-d.DX, -d.DY}
+}
+
+func (d Dir) String() string {
+	switch d {
+	case North:
+		return "N"
+	case South:
+		return "S"
+	case East:
+		return "E"
+	case West:
+		return "W"
+	case NorthEast:
+		return "NE"
+	case SouthEast:
+		return "SE"
+	case SouthWest:
+		return "SW"
+	case NorthWest:
+		return "NW"
+	}
+	return fmt.Sprintf("[%+d %+d]", d.DX, d.DY)
+}
+
+// Grid is a rectangular grid of cells, stored row by row.
+type Grid[T any] struct {
+	width, height int
+	cells         []T
+}
+
+// New returns a width x height grid of zero values.
+func New[T any](width, height int) *Grid[T] {
+	return &Grid[T]{
+		width:  width,
+		height: height,
+		cells:  make([]T, width*height),
+	}
+}
+
+// FromLines builds a grid with one row per line and one cell per rune,
+// converted by conv. Trailing newlines are ignored; all lines must otherwise
+// have the same length.
+func FromLines[T any](lines []string, conv func(p Point, r rune) T) (*Grid[T], error) {
+	var g *Grid[T]
+	for y, line := range lines {
+		row := []rune(strings.TrimRight(line, "\r\n"))
+		if g == nil {
+			g = New[T](len(row), len(lines))
+		}
+		if len(row) != g.width {
+			return nil, lib.Pos{Line: y + 1}.Errorf("", "width %d, want %d", len(row), g.width)
+		}
+		for x, r := range row {
+			p := Point{x, y}
+			g.cells[g.index(p)] = conv(p, r)
+		}
+	}
+	if g == nil {
+		return New[T](0, 0), nil
+	}
+	return g, nil
+}
+
+// Runes builds a grid holding the input runes as-is.
+func Runes(lines []string) (*Grid[rune], error) {
+	return FromLines(lines, func(_ Point, r rune) rune { return r })
+}
+
+func (g *Grid[T]) Width() int  { return g.width }
+func (g *Grid[T]) Height() int { return g.height }
+
+func (g *Grid[T]) index(p Point) int {
+	return p.Y*g.width + p.X
+}
+
+// InBounds reports whether p is a cell of the grid.
+func (g *Grid[T]) InBounds(p Point) bool {
+	return p.X >= 0 && p.X < g.width && p.Y >= 0 && p.Y < g.height
+}
+
+// Get returns the value at p, and false if p is out of bounds.
+func (g *Grid[T]) Get(p Point) (T, bool) {
+	if !g.InBounds(p) {
+		var zero T
+		return zero, false
+	}
+	return g.cells[g.index(p)], true
+}
+
+// At returns the value at p, or the zero value if p is out of bounds.
+func (g *Grid[T]) At(p Point) T {
+	v, _ := g.Get(p)
+	return v
+}
+
+// Set stores v at p. It returns false, leaving the grid unchanged, if p is out
+// of bounds.
+func (g *Grid[T]) Set(p Point, v T) bool {
+	if !g.InBounds(p) {
+		return false
+	}
+	g.cells[g.index(p)] = v
+	return true
+}
+
+// Fill sets every cell to v.
+func (g *Grid[T]) Fill(v T) {
+	for i := range g.cells {
+		g.cells[i] = v
+	}
+}
+
+// Row returns a copy of row y.
+func (g *Grid[T]) Row(y int) []T {
+	res := make([]T, g.width)
+	copy(res, g.cells[y*g.width:(y+1)*g.width])
+	return res
+}
+
+// Col returns a copy of column x.
+func (g *Grid[T]) Col(x int) []T {
+	res := make([]T, g.height)
+	for y := range res {
+		res[y] = g.cells[g.index(Point{x, y})]
+	}
+	return res
+}
+
+// Neighbors returns the in-bounds cells one step from p in each of dirs.
+func (g *Grid[T]) Neighbors(p Point, dirs []Dir) []Point {
+	res := make([]Point, 0, len(dirs))
+	for _, d := range dirs {
+		if q := p.Add(d); g.InBounds(q) {
+			res = append(res, q)
+		}
+	}
+	return res
+}
+
+// Neighbors4 returns the in-bounds orthogonal neighbours of p.
+func (g *Grid[T]) Neighbors4(p Point) []Point {
+	return g.Neighbors(p, Dirs4)
+}
+
+// Neighbors8 returns the in-bounds orthogonal and diagonal neighbours of p.
+func (g *Grid[T]) Neighbors8(p Point) []Point {
+	return g.Neighbors(p, Dirs8)
+}
+
+// Each calls fn for every cell, row by row.
+func (g *Grid[T]) Each(fn func(p Point, v T)) {
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			p := Point{x, y}
+			fn(p, g.cells[g.index(p)])
+		}
+	}
+}
+
+// FindAll returns the points of every cell matching pred, row by row.
+func (g *Grid[T]) FindAll(pred func(v T) bool) []Point {
+	var res []Point
+	g.Each(func(p Point, v T) {
+		if pred(v) {
+			res = append(res, p)
+		}
+	})
+	return res
+}
+
+// Clone returns a copy of the grid.
+func (g *Grid[T]) Clone() *Grid[T] {
+	res := New[T](g.width, g.height)
+	copy(res.cells, g.cells)
+	return res
+}
+
+// remap builds a width x height grid where each cell is copied from g at the
+// point returned by src.
+func (g *Grid[T]) remap(width, height int, src func(p Point) Point) *Grid[T] {
+	res := New[T](width, height)
+	res.Each(func(p Point, _ T) {
+		res.cells[res.index(p)] = g.cells[g.index(src(p))]
+	})
+	return res
+}
+
+// Transpose returns a new grid with rows and columns swapped.
+func (g *Grid[T]) Transpose() *Grid[T] {
+	return g.remap(g.height, g.width, func(p Point) Point {
+		return Point{p.Y, p.X}
+	})
+}
+
+// RotateCW returns a new grid rotated a quarter turn clockwise.
+func (g *Grid[T]) RotateCW() *Grid[T] {
+	return g.remap(g.height, g.width, func(p Point) Point {
+		return Point{p.Y, g.height - 1 - p.X}
+	})
+}
+
+// RotateCCW returns a new grid rotated a quarter turn counter-clockwise.
+func (g *Grid[T]) RotateCCW() *Grid[T] {
+	return g.remap(g.height, g.width, func(p Point) Point {
+		return Point{g.width - 1 - p.Y, p.X}
+	})
+}
+
+// FlipH returns a new grid mirrored left to right.
+func (g *Grid[T]) FlipH() *Grid[T] {
+	return g.remap(g.width, g.height, func(p Point) Point {
+		return Point{g.width - 1 - p.X, p.Y}
+	})
+}
+
+// FlipV returns a new grid mirrored top to bottom.
+func (g *Grid[T]) FlipV() *Grid[T] {
+	return g.remap(g.width, g.height, func(p Point) Point {
+		return Point{p.X, g.height - 1 - p.Y}
+	})
+}
+
+// Render draws the grid as text, one line per row, using cell to draw each
+// cell.
+func (g *Grid[T]) Render(cell func(p Point, v T) string) string {
+	var sb strings.Builder
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			p := Point{x, y}
+			sb.WriteString(cell(p, g.cells[g.index(p)]))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// String draws the grid with each cell formatted by %v, except that runes
+// (and so int32s) are drawn as characters.
+func (g *Grid[T]) String() string {
+	return g.Render(func(_ Point, v T) string {
+		if r, ok := any(v).(rune); ok {
+			return string(r)
+		}
+		return fmt.Sprint(v)
+	})
+}
@@ -0,0 +1,97 @@
+package grid
+
+import (
+	"strings"
+	"testing"
+)
+
+func mustRunes(t *testing.T, s string) *Grid[rune] {
+	t.Helper()
+	g, err := Runes(strings.SplitAfter(strings.TrimSuffix(s, "\n"), "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestString(t *testing.T) {
+	const s = "da\n#.\n"
+	if got := mustRunes(t, s).String(); got != s {
+		t.Errorf("Grid[rune].String() = %q, want %q", got, s)
+	}
+
+	ints := New[int](3, 1)
+	ints.Set(Point{1, 0}, 7)
+	if got, want := ints.String(), "070\n"; got != want {
+		t.Errorf("Grid[int].String() = %q, want %q", got, want)
+	}
+}
+
+func TestTransform(t *testing.T) {
+	g := mustRunes(t, "abc\ndef\n")
+	tests := []struct {
+		name string
+		fn   func(*Grid[rune]) *Grid[rune]
+		want string
+	}{
+		{"Transpose", (*Grid[rune]).Transpose, "ad\nbe\ncf\n"},
+		{"RotateCW", (*Grid[rune]).RotateCW, "da\neb\nfc\n"},
+		{"RotateCCW", (*Grid[rune]).RotateCCW, "cf\nbe\nad\n"},
+		{"FlipH", (*Grid[rune]).FlipH, "cba\nfed\n"},
+		{"FlipV", (*Grid[rune]).FlipV, "def\nabc\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			res := tc.fn(g)
+			if got := res.String(); got != tc.want {
+				t.Errorf("got\n%swant\n%s", got, tc.want)
+			}
+			if g.String() != "abc\ndef\n" {
+				t.Errorf("original grid changed to\n%s", g)
+			}
+		})
+	}
+}
+
+func TestTransformIdentities(t *testing.T) {
+	g := mustRunes(t, "abcd\nefgh\nijkl\n")
+	want := g.String()
+	tests := []struct {
+		name string
+		got  *Grid[rune]
+	}{
+		{"Transpose twice", g.Transpose().Transpose()},
+		{"RotateCW then RotateCCW", g.RotateCW().RotateCCW()},
+		{"RotateCW four times", g.RotateCW().RotateCW().RotateCW().RotateCW()},
+		{"FlipH twice", g.FlipH().FlipH()},
+		{"FlipV twice", g.FlipV().FlipV()},
+		{"FlipH FlipV is a half turn", g.FlipH().FlipV().RotateCW().RotateCW()},
+		{"Transpose is RotateCW then FlipH", g.RotateCW().FlipH().Transpose()},
+	}
+	for _, tc := range tests {
+		if got := tc.got.String(); got != want {
+			t.Errorf("%s: got\n%swant\n%s", tc.name, got, want)
+		}
+	}
+}
+
+func TestBounds(t *testing.T) {
+	g := mustRunes(t, "ab\ncd\n")
+	if v, ok := g.Get(Point{1, 1}); !ok || v != 'd' {
+		t.Errorf("Get(1,1) = %q, %v, want 'd', true", v, ok)
+	}
+	for _, p := range []Point{{-1, 0}, {2, 0}, {0, 2}, {0, -1}} {
+		if _, ok := g.Get(p); ok {
+			t.Errorf("Get(%v) in bounds", p)
+		}
+		if g.Set(p, 'x') {
+			t.Errorf("Set(%v) succeeded", p)
+		}
+	}
+	if got := len(g.Neighbors8(Point{0, 0})); got != 3 {
+		t.Errorf("corner has %d neighbours, want 3", got)
+	}
+	if _, err := Runes([]string{"ab\n", "c\n"}); err == nil {
+		t.Error("ragged lines gave no error")
+	}
+}
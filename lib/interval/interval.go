@@ -0,0 +1,244 @@
+// Package interval provides half-open integer intervals, normalized sets of
+// them, and mappings that shift whole sets at once instead of one value at a
+// time.
+package interval
+
+import (
+	"aoc23/lib"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Interval is the half-open range [Lo, Hi). It is empty if Hi <= Lo.
+type Interval struct {
+	Lo, Hi int
+}
+
+// FromLen returns the interval of n values starting at lo.
+func FromLen(lo, n int) Interval {
+	return Interval{lo, lo + n}
+}
+
+func (iv Interval) String() string {
+	return fmt.Sprintf("[%d,%d)", iv.Lo, iv.Hi)
+}
+
+// Len returns the number of values in the interval.
+func (iv Interval) Len() int {
+	if iv.Empty() {
+		return 0
+	}
+	return iv.Hi - iv.Lo
+}
+
+// Empty reports whether the interval holds no values.
+func (iv Interval) Empty() bool {
+	return iv.Hi <= iv.Lo
+}
+
+// Contains reports whether x is in the interval.
+func (iv Interval) Contains(x int) bool {
+	return x >= iv.Lo && x < iv.Hi
+}
+
+// Overlaps reports whether the intervals share at least one value.
+func (iv Interval) Overlaps(o Interval) bool {
+	return !iv.Intersect(o).Empty()
+}
+
+// Intersect returns the values in both intervals, which may be empty.
+func (iv Interval) Intersect(o Interval) Interval {
+	return Interval{lib.Max(iv.Lo, o.Lo), lib.Min(iv.Hi, o.Hi)}
+}
+
+// SplitAt cuts the interval into the values below x and the values at or
+// above x. Either half may be empty.
+func (iv Interval) SplitAt(x int) (Interval, Interval) {
+	x = lib.Max(iv.Lo, lib.Min(x, iv.Hi))
+	return Interval{iv.Lo, x}, Interval{x, iv.Hi}
+}
+
+// Shift returns the interval moved by d.
+func (iv Interval) Shift(d int) Interval {
+	return Interval{iv.Lo + d, iv.Hi + d}
+}
+
+// Set is a set of integers stored as sorted, disjoint, non-adjacent
+// intervals. The zero value is the empty set.
+type Set struct {
+	ivs []Interval
+}
+
+// NewSet returns the union of the given intervals.
+func NewSet(ivs ...Interval) Set {
+	var res []Interval
+	for _, iv := range ivs {
+		if !iv.Empty() {
+			res = append(res, iv)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Lo < res[j].Lo
+	})
+
+	// Merge overlapping and touching intervals.
+	n := 0
+	for _, iv := range res {
+		if n > 0 && iv.Lo <= res[n-1].Hi {
+			res[n-1].Hi = lib.Max(res[n-1].Hi, iv.Hi)
+			continue
+		}
+		res[n] = iv
+		n++
+	}
+	return Set{res[:n]}
+}
+
+// Intervals returns a copy of the set's intervals, in order.
+func (s Set) Intervals() []Interval {
+	return append([]Interval(nil), s.ivs...)
+}
+
+// Len returns the number of values in the set.
+func (s Set) Len() int {
+	n := 0
+	for _, iv := range s.ivs {
+		n += iv.Len()
+	}
+	return n
+}
+
+// Empty reports whether the set holds no values.
+func (s Set) Empty() bool {
+	return len(s.ivs) == 0
+}
+
+// Min returns the smallest value in the set, and false if it is empty.
+func (s Set) Min() (int, bool) {
+	if s.Empty() {
+		return 0, false
+	}
+	return s.ivs[0].Lo, true
+}
+
+// Contains reports whether x is in the set.
+func (s Set) Contains(x int) bool {
+	i := sort.Search(len(s.ivs), func(i int) bool {
+		return s.ivs[i].Hi > x
+	})
+	return i < len(s.ivs) && s.ivs[i].Contains(x)
+}
+
+// Union returns the values in either set.
+func (s Set) Union(o Set) Set {
+	return NewSet(append(s.Intervals(), o.ivs...)...)
+}
+
+// Intersect returns the values in both sets.
+func (s Set) Intersect(o Set) Set {
+	var res []Interval
+	for i, j := 0, 0; i < len(s.ivs) && j < len(o.ivs); {
+		if iv := s.ivs[i].Intersect(o.ivs[j]); !iv.Empty() {
+			res = append(res, iv)
+		}
+		if s.ivs[i].Hi < o.ivs[j].Hi {
+			i++
+		} else {
+			j++
+		}
+	}
+	return NewSet(res...)
+}
+
+// Subtract returns the values in s that are not in o.
+func (s Set) Subtract(o Set) Set {
+	var res []Interval
+	j := 0
+	for _, iv := range s.ivs {
+		// Skip the parts of o entirely below this interval.
+		for j < len(o.ivs) && o.ivs[j].Hi <= iv.Lo {
+			j++
+		}
+		for k := j; k < len(o.ivs) && o.ivs[k].Lo < iv.Hi; k++ {
+			below, rest := iv.SplitAt(o.ivs[k].Lo)
+			res = append(res, below)
+			_, iv = rest.SplitAt(o.ivs[k].Hi)
+		}
+		res = append(res, iv)
+	}
+	return NewSet(res...)
+}
+
+// SplitAt cuts the set into the values below x and the values at or above x.
+func (s Set) SplitAt(x int) (Set, Set) {
+	var lo, hi []Interval
+	for _, iv := range s.ivs {
+		a, b := iv.SplitAt(x)
+		lo = append(lo, a)
+		hi = append(hi, b)
+	}
+	return NewSet(lo...), NewSet(hi...)
+}
+
+func (s Set) String() string {
+	parts := make([]string, len(s.ivs))
+	for i, iv := range s.ivs {
+		parts[i] = iv.String()
+	}
+	return "{" + strings.Join(parts, " ") + "}"
+}
+
+// Rule sends the Len values starting at Src to the values starting at Dst.
+type Rule struct {
+	Src, Dst, Len int
+}
+
+// Source returns the interval of values the rule applies to.
+func (r Rule) Source() Interval {
+	return FromLen(r.Src, r.Len)
+}
+
+func (r Rule) String() string {
+	return fmt.Sprintf("%v => %v", r.Source(), FromLen(r.Dst, r.Len))
+}
+
+// Mapping is a list of rules. Values not covered by any rule map to
+// themselves; where rules overlap, the earliest one wins.
+type Mapping struct {
+	rules []Rule
+}
+
+// NewMapping returns a mapping applying the rules in order.
+func NewMapping(rules ...Rule) *Mapping {
+	return &Mapping{rules: append([]Rule(nil), rules...)}
+}
+
+// Rules returns a copy of the mapping's rules.
+func (m *Mapping) Rules() []Rule {
+	return append([]Rule(nil), m.rules...)
+}
+
+// Map returns the image of a single value.
+func (m *Mapping) Map(x int) int {
+	for _, r := range m.rules {
+		if r.Source().Contains(x) {
+			return r.Dst + (x - r.Src)
+		}
+	}
+	return x
+}
+
+// Apply returns the image of every value in s.
+func (m *Mapping) Apply(s Set) Set {
+	var res []Interval
+	rest := s
+	for _, r := range m.rules {
+		hit := rest.Intersect(NewSet(r.Source()))
+		for _, iv := range hit.ivs {
+			res = append(res, iv.Shift(r.Dst-r.Src))
+		}
+		rest = rest.Subtract(hit)
+	}
+	return NewSet(append(res, rest.ivs...)...)
+}
@@ -0,0 +1,169 @@
+package interval
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestInterval(t *testing.T) {
+	iv := Interval{3, 8}
+	if iv.Len() != 5 || iv.Empty() {
+		t.Errorf("%v: Len %d, Empty %v", iv, iv.Len(), iv.Empty())
+	}
+	if empty := (Interval{5, 2}); empty.Len() != 0 || !empty.Empty() {
+		t.Errorf("%v: Len %d, Empty %v", empty, empty.Len(), empty.Empty())
+	}
+	if FromLen(3, 5) != iv {
+		t.Errorf("FromLen(3, 5) = %v, want %v", FromLen(3, 5), iv)
+	}
+
+	tests := []struct {
+		o         Interval
+		intersect Interval
+		overlaps  bool
+	}{
+		{Interval{0, 3}, Interval{3, 3}, false},
+		{Interval{0, 4}, Interval{3, 4}, true},
+		{Interval{4, 6}, Interval{4, 6}, true},
+		{Interval{7, 20}, Interval{7, 8}, true},
+		{Interval{8, 20}, Interval{8, 8}, false},
+	}
+	for _, tc := range tests {
+		if got := iv.Intersect(tc.o); got != tc.intersect {
+			t.Errorf("%v.Intersect(%v) = %v, want %v", iv, tc.o, got, tc.intersect)
+		}
+		if got := iv.Overlaps(tc.o); got != tc.overlaps {
+			t.Errorf("%v.Overlaps(%v) = %v, want %v", iv, tc.o, got, tc.overlaps)
+		}
+	}
+
+	for _, tc := range []struct {
+		x      int
+		lo, hi Interval
+	}{
+		{0, Interval{3, 3}, Interval{3, 8}},
+		{5, Interval{3, 5}, Interval{5, 8}},
+		{10, Interval{3, 8}, Interval{8, 8}},
+	} {
+		if lo, hi := iv.SplitAt(tc.x); lo != tc.lo || hi != tc.hi {
+			t.Errorf("%v.SplitAt(%d) = %v, %v, want %v, %v", iv, tc.x, lo, hi, tc.lo, tc.hi)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Set
+		want string
+	}{
+		{"zero", Set{}, "{}"},
+		{"merge", NewSet(Interval{5, 8}, Interval{0, 2}, Interval{1, 3}, Interval{8, 9}, Interval{4, 4}), "{[0,3) [5,9)}"},
+		{"union", NewSet(Interval{0, 2}).Union(NewSet(Interval{2, 4}, Interval{6, 7})), "{[0,4) [6,7)}"},
+		{"intersect", NewSet(Interval{0, 5}, Interval{8, 12}).Intersect(NewSet(Interval{3, 9}, Interval{11, 20})), "{[3,5) [8,9) [11,12)}"},
+		{"subtract", NewSet(Interval{0, 10}).Subtract(NewSet(Interval{2, 3}, Interval{5, 7}, Interval{9, 15})), "{[0,2) [3,5) [7,9)}"},
+		{"subtract all", NewSet(Interval{2, 4}).Subtract(NewSet(Interval{0, 10})), "{}"},
+	}
+	for _, tc := range tests {
+		if got := tc.got.String(); got != tc.want {
+			t.Errorf("%s: got %s, want %s", tc.name, got, tc.want)
+		}
+	}
+
+	s := NewSet(Interval{0, 3}, Interval{5, 9})
+	if s.Len() != 7 {
+		t.Errorf("%v.Len() = %d, want 7", s, s.Len())
+	}
+	if min, ok := s.Min(); min != 0 || !ok {
+		t.Errorf("%v.Min() = %d, %v, want 0, true", s, min, ok)
+	}
+	if _, ok := (Set{}).Min(); ok {
+		t.Errorf("empty set has a Min")
+	}
+	lo, hi := s.SplitAt(6)
+	if lo.String() != "{[0,3) [5,6)}" || hi.String() != "{[6,9)}" {
+		t.Errorf("%v.SplitAt(6) = %v, %v", s, lo, hi)
+	}
+}
+
+// randomSet returns a set of values in [0, 60), along with the same values
+// as a map.
+func randomSet(rng *rand.Rand) (Set, map[int]bool) {
+	var ivs []Interval
+	vals := make(map[int]bool)
+	for i := rng.Intn(5); i > 0; i-- {
+		lo := rng.Intn(50)
+		iv := Interval{lo, lo + rng.Intn(10)}
+		ivs = append(ivs, iv)
+		for x := iv.Lo; x < iv.Hi; x++ {
+			vals[x] = true
+		}
+	}
+	return NewSet(ivs...), vals
+}
+
+// checkSet compares s with the values in want, and checks s is normalized.
+func checkSet(t *testing.T, what string, s Set, want map[int]bool) {
+	t.Helper()
+	n := 0
+	for x := -5; x < 120; x++ {
+		if s.Contains(x) != want[x] {
+			t.Errorf("%s = %v: Contains(%d) = %v", what, s, x, s.Contains(x))
+		}
+		if want[x] {
+			n++
+		}
+	}
+	if s.Len() != n {
+		t.Errorf("%s = %v: Len() = %d, want %d", what, s, s.Len(), n)
+	}
+	for i, iv := range s.ivs {
+		if iv.Empty() || (i > 0 && iv.Lo <= s.ivs[i-1].Hi) {
+			t.Errorf("%s = %v is not normalized", what, s)
+		}
+	}
+}
+
+// TestSetOps checks the set operations against the same operations on maps.
+func TestSetOps(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		a, av := randomSet(rng)
+		b, bv := randomSet(rng)
+		union, inter, diff := make(map[int]bool), make(map[int]bool), make(map[int]bool)
+		for x := range av {
+			union[x] = true
+			inter[x] = bv[x]
+			diff[x] = !bv[x]
+		}
+		for x := range bv {
+			union[x] = true
+		}
+		checkSet(t, "a", a, av)
+		checkSet(t, a.String()+" ∪ "+b.String(), a.Union(b), union)
+		checkSet(t, a.String()+" ∩ "+b.String(), a.Intersect(b), inter)
+		checkSet(t, a.String()+" - "+b.String(), a.Subtract(b), diff)
+	}
+}
+
+func TestMapping(t *testing.T) {
+	// Day 5's sample seed-to-soil map, plus an overlapping rule that loses.
+	m := NewMapping(Rule{98, 50, 2}, Rule{50, 52, 48}, Rule{90, 0, 20})
+	for _, tc := range []struct{ x, want int }{
+		{0, 0}, {49, 49}, {50, 52}, {79, 81}, {97, 99}, {98, 50}, {99, 51}, {100, 10}, {110, 110},
+	} {
+		if got := m.Map(tc.x); got != tc.want {
+			t.Errorf("Map(%d) = %d, want %d", tc.x, got, tc.want)
+		}
+	}
+
+	s := NewSet(Interval{45, 55}, Interval{95, 105})
+	got := m.Apply(s)
+	want := make(map[int]bool)
+	for _, iv := range s.Intervals() {
+		for x := iv.Lo; x < iv.Hi; x++ {
+			want[m.Map(x)] = true
+		}
+	}
+	checkSet(t, "Apply", got, want)
+}
@@ -2,14 +2,21 @@ package lib
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"io"
 	"os"
 )
 
 type TextIterator interface {
+	// NextLine returns the next line, including its trailing newline.
 	NextLine() (string, error)
 	Close()
+
+	// Lines and LinesContext walk the remaining input line by line (or
+	// block by block), trimmed, with positions.
+	Lines() *LineIterator
+	LinesContext(ctx context.Context) *LineIterator
 }
 
 type bufioIterator struct {
@@ -32,6 +39,14 @@ func (bii *bufioIterator) Close() {
 	bii.ogr.Close()
 }
 
+func (bii *bufioIterator) Lines() *LineIterator {
+	return NewLineIterator(context.Background(), bii)
+}
+
+func (bii *bufioIterator) LinesContext(ctx context.Context) *LineIterator {
+	return NewLineIterator(ctx, bii)
+}
+
 func GetInputIterator(r io.ReadCloser) (TextIterator, error) {
 	return &bufioIterator{r, bufio.NewReader(r)}, nil
 }
@@ -0,0 +1,129 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Line is a single input line, with surrounding whitespace (including the
+// newline) trimmed.
+type Line struct {
+	Text   string
+	Num    int   // 1-based line number
+	Offset int64 // byte offset of the start of the line in the input
+}
+
+// LineIterator walks the lines of a TextIterator:
+//
+//	lines := it.Lines()
+//	for lines.Next() {
+//		l := lines.Line()
+//		...
+//	}
+//	if err := lines.Err(); err != nil {
+//		...
+//	}
+//
+// Only the current line (or block) is held in memory.
+type LineIterator struct {
+	ctx    context.Context
+	it     TextIterator
+	line   Line
+	block  []Line
+	num    int
+	offset int64
+	err    error
+}
+
+// NewLineIterator returns a LineIterator over it which stops early, with
+// ctx's error, once ctx is done.
+func NewLineIterator(ctx context.Context, it TextIterator) *LineIterator {
+	return &LineIterator{ctx: ctx, it: it}
+}
+
+// Next advances to the next line, returning false at the end of the input or
+// on error.
+func (li *LineIterator) Next() bool {
+	if li.err != nil {
+		return false
+	}
+	if err := li.ctx.Err(); err != nil {
+		li.err = err
+		return false
+	}
+	data, err := li.it.NextLine()
+	if err != nil {
+		li.err = err
+		return false
+	}
+	li.num++
+	li.line = Line{Text: strings.TrimSpace(data), Num: li.num, Offset: li.offset}
+	li.offset += int64(len(data))
+	return true
+}
+
+// Line returns the current line.
+func (li *LineIterator) Line() Line {
+	return li.line
+}
+
+// NextBlock advances to the next block of non-blank lines, skipping the blank
+// lines before it. It returns false once no blocks are left.
+func (li *LineIterator) NextBlock() bool {
+	li.block = nil
+	for li.Next() {
+		if li.line.Text == "" {
+			if len(li.block) > 0 {
+				return true
+			}
+			continue
+		}
+		li.block = append(li.block, li.line)
+	}
+	return len(li.block) > 0
+}
+
+// Block returns the lines of the current block.
+func (li *LineIterator) Block() []Line {
+	return li.block
+}
+
+// Err returns the error that stopped the iteration, or nil if the end of the
+// input was reached.
+func (li *LineIterator) Err() error {
+	if errors.Is(li.err, io.EOF) {
+		return nil
+	}
+	return li.err
+}
+
+type sliceIterator struct {
+	lines []string
+}
+
+func (si *sliceIterator) NextLine() (string, error) {
+	if len(si.lines) == 0 {
+		return "", io.EOF
+	}
+	line := si.lines[0]
+	si.lines = si.lines[1:]
+	return line, nil
+}
+
+func (si *sliceIterator) Close() {}
+
+func (si *sliceIterator) Lines() *LineIterator {
+	return NewLineIterator(context.Background(), si)
+}
+
+func (si *sliceIterator) LinesContext(ctx context.Context) *LineIterator {
+	return NewLineIterator(ctx, si)
+}
+
+// NewSliceIterator returns a TextIterator over lines already in memory, such
+// as those passed to Day.Parse.
+func NewSliceIterator(lines []string) TextIterator {
+	return &sliceIterator{lines}
+}
@@ -0,0 +1,78 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNextBlock(t *testing.T) {
+	tests := []struct {
+		lines []string
+		want  [][]string
+	}{
+		{nil, nil},
+		{[]string{"\n", "  \n", "\n"}, nil},
+		{[]string{"a\n", "b\n"}, [][]string{{"a", "b"}}},
+		{[]string{"a\n", "\n", "b\n", "c"}, [][]string{{"a"}, {"b", "c"}}},
+		{[]string{"\n", "\n", "a\n", "\n", "\n", "\n", "b\n", "\n", "\n"}, [][]string{{"a"}, {"b"}}},
+		{[]string{"a\r\n", " \r\n", "\r\n", "b\r\n"}, [][]string{{"a"}, {"b"}}},
+	}
+	for _, tc := range tests {
+		lines := NewSliceIterator(tc.lines).Lines()
+		var got [][]string
+		for lines.NextBlock() {
+			var block []string
+			for _, l := range lines.Block() {
+				block = append(block, l.Text)
+			}
+			got = append(got, block)
+		}
+		if err := lines.Err(); err != nil {
+			t.Errorf("%q: Err() = %v", tc.lines, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%q: blocks = %q, want %q", tc.lines, got, tc.want)
+		}
+	}
+}
+
+func TestLineOffset(t *testing.T) {
+	lines := NewSliceIterator([]string{"ab\r\n", "\r\n", "  cde \r\n", "f"}).Lines()
+	want := []Line{
+		{Text: "ab", Num: 1, Offset: 0},
+		{Text: "", Num: 2, Offset: 4},
+		{Text: "cde", Num: 3, Offset: 6},
+		{Text: "f", Num: 4, Offset: 14},
+	}
+	var got []Line
+	for lines.Next() {
+		got = append(got, lines.Line())
+	}
+	if err := lines.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("lines = %+v, want %+v", got, want)
+	}
+}
+
+func TestLinesContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lines := NewSliceIterator([]string{"a\n", "b\n", "\n", "c\n"}).LinesContext(ctx)
+	if !lines.Next() || lines.Line().Text != "a" {
+		t.Fatalf("first line = %+v, want a", lines.Line())
+	}
+	cancel()
+	if lines.Next() {
+		t.Errorf("Next() after cancel read %+v", lines.Line())
+	}
+	if lines.NextBlock() {
+		t.Errorf("NextBlock() after cancel read %+v", lines.Block())
+	}
+	if err := lines.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Err() = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,93 @@
+package lib
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// LogLevel controls how much diagnostic output a Logger emits. Levels are
+// listed in increasing order of verbosity.
+type LogLevel int
+
+const (
+	LevelError LogLevel = iota
+	LevelInfo
+	LevelDebug
+	LevelTrace
+)
+
+func (ll LogLevel) String() string {
+	switch ll {
+	case LevelError:
+		return "error"
+	case LevelInfo:
+		return "info"
+	case LevelDebug:
+		return "debug"
+	case LevelTrace:
+		return "trace"
+	default:
+		return "invalid"
+	}
+}
+
+// ParseLogLevel converts a level name (as returned by LogLevel.String) back
+// into a LogLevel.
+func ParseLogLevel(s string) (LogLevel, error) {
+	for ll := LevelError; ll <= LevelTrace; ll++ {
+		if strings.EqualFold(s, ll.String()) {
+			return ll, nil
+		}
+	}
+	return LevelError, fmt.Errorf("unknown log level %q", s)
+}
+
+var (
+	logOutput   = log.New(os.Stderr, "", log.LstdFlags)
+	logLevel    = LevelError
+	scopeLevels = make(map[string]LogLevel)
+)
+
+// SetLogLevel sets the level for every scope without its own override.
+func SetLogLevel(ll LogLevel) {
+	logLevel = ll
+}
+
+// SetScopeLogLevel overrides the level for a single scope (e.g. "day07").
+func SetScopeLogLevel(scope string, ll LogLevel) {
+	scopeLevels[scope] = ll
+}
+
+// Logger writes leveled diagnostics to stderr, tagged with its scope. Levels
+// must be configured before any solver starts running.
+type Logger struct {
+	scope string
+}
+
+func NewLogger(scope string) *Logger {
+	return &Logger{scope: scope}
+}
+
+// Enabled reports whether messages at level ll are emitted. Use it to skip
+// building expensive diagnostics that would be discarded.
+func (l *Logger) Enabled(ll LogLevel) bool {
+	cur, ok := scopeLevels[l.scope]
+	if !ok {
+		cur = logLevel
+	}
+	return ll <= cur
+}
+
+func (l *Logger) logf(ll LogLevel, msgfmt string, args ...any) {
+	if !l.Enabled(ll) {
+		return
+	}
+	logOutput.Printf("[%s] %s", l.scope, fmt.Sprintf(msgfmt, args...))
+}
+
+func (l *Logger) Errorf(msgfmt string, args ...any) { l.logf(LevelError, msgfmt, args...) }
+func (l *Logger) Infof(msgfmt string, args ...any)  { l.logf(LevelInfo, msgfmt, args...) }
+func (l *Logger) Debugf(msgfmt string, args ...any) { l.logf(LevelDebug, msgfmt, args...) }
+func (l *Logger) Tracef(msgfmt string, args ...any) { l.logf(LevelTrace, msgfmt, args...) }
@@ -0,0 +1,252 @@
+// Package numtheory provides integer number theory: gcd and lcm without
+// silent overflow, modular arithmetic, the Chinese Remainder Theorem, integer
+// square roots, primality and factorization.
+//
+// Functions are generic over Go's integer types. Results that may not fit
+// come with an ok flag or error, and a big.Int counterpart.
+package numtheory
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/bits"
+
+	"golang.org/x/exp/constraints"
+)
+
+var (
+	// ErrNoSolution is returned by CRT for inconsistent congruences.
+	ErrNoSolution = errors.New("numtheory: no solution")
+
+	// ErrOverflow is returned when a result does not fit the requested type.
+	ErrOverflow = errors.New("numtheory: result out of range")
+)
+
+func abs[T constraints.Integer](a T) T {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// GCD returns the greatest common divisor of a and b, which is never
+// negative. GCD(0, 0) is 0.
+func GCD[T constraints.Integer](a, b T) T {
+	a, b = abs(a), abs(b)
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// ExtGCD returns g = GCD(a, b) along with x and y such that a*x + b*y = g.
+func ExtGCD[T constraints.Signed](a, b T) (g, x, y T) {
+	oldR, r := a, b
+	oldX, x := T(1), T(0)
+	oldY, y := T(0), T(1)
+	for r != 0 {
+		q := oldR / r
+		oldR, r = r, oldR-q*r
+		oldX, x = x, oldX-q*x
+		oldY, y = y, oldY-q*y
+	}
+	if oldR < 0 {
+		return -oldR, -oldX, -oldY
+	}
+	return oldR, oldX, oldY
+}
+
+// LCM returns the least common multiple of vs, which is never negative, or
+// false if it does not fit in T; see BigLCM. The LCM of nothing is 1, and of
+// anything with 0 is 0.
+func LCM[T constraints.Integer](vs ...T) (T, bool) {
+	res := T(1)
+	for _, v := range vs {
+		v = abs(v)
+		if v == 0 {
+			return 0, true
+		}
+		f := res / GCD(res, v)
+		prod := f * v
+		if prod/v != f || prod < 0 {
+			return 0, false
+		}
+		res = prod
+	}
+	return res, true
+}
+
+// BigLCM returns the least common multiple of vs, however large.
+func BigLCM[T constraints.Integer](vs ...T) *big.Int {
+	res := big.NewInt(1)
+	for _, v := range vs {
+		b := toBig(v)
+		b.Abs(b)
+		if b.Sign() == 0 {
+			return b
+		}
+		g := new(big.Int).GCD(nil, nil, res, b)
+		res.Mul(res.Quo(res, g), b)
+	}
+	return res
+}
+
+// toBig converts any integer to a big.Int.
+func toBig[T constraints.Integer](v T) *big.Int {
+	if v < 0 {
+		return big.NewInt(int64(v))
+	}
+	return new(big.Int).SetUint64(uint64(v))
+}
+
+// fromBig converts b to T, or returns false if it does not fit.
+func fromBig[T constraints.Integer](b *big.Int) (T, bool) {
+	var v T
+	switch {
+	case b.IsInt64():
+		v = T(b.Int64())
+	case b.IsUint64():
+		v = T(b.Uint64())
+	default:
+		return 0, false
+	}
+	return v, toBig(v).Cmp(b) == 0
+}
+
+// mod returns a mod m in [0, m), for m > 0.
+func mod[T constraints.Integer](a, m T) T {
+	a %= m
+	if a < 0 {
+		a += m
+	}
+	return a
+}
+
+// mulMod returns a*b mod m without overflow, for a, b < m.
+func mulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	_, rem := bits.Div64(hi%m, lo, m)
+	return rem
+}
+
+func powMod(base, exp, m uint64) uint64 {
+	res := uint64(1) % m
+	base %= m
+	for ; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			res = mulMod(res, base, m)
+		}
+		base = mulMod(base, base, m)
+	}
+	return res
+}
+
+// ModPow returns base**exp mod m, in [0, m). It panics unless m > 0 and
+// exp >= 0.
+func ModPow[T constraints.Integer](base, exp, m T) T {
+	if m <= 0 || exp < 0 {
+		panic("numtheory: ModPow needs m > 0 and exp >= 0")
+	}
+	return T(powMod(uint64(mod(base, m)), uint64(exp), uint64(m)))
+}
+
+// ModInverse returns x in [0, m) with a*x = 1 mod m, or false if there is
+// none because a and m share a factor. It panics unless m > 0.
+func ModInverse[T constraints.Signed](a, m T) (T, bool) {
+	if m <= 0 {
+		panic("numtheory: ModInverse needs m > 0")
+	}
+	g, x, _ := ExtGCD(mod(a, m), m)
+	if g != 1 {
+		return 0, false
+	}
+	return mod(x, m), true
+}
+
+// CRT solves the congruences x = residues[i] mod moduli[i], returning the
+// solution as x = r mod m with r in [0, m). The moduli need not be coprime,
+// in which case m is their LCM and the congruences may have no solution
+// (ErrNoSolution). If r or m do not fit in T, CRT returns ErrOverflow; see
+// BigCRT.
+func CRT[T constraints.Signed](residues, moduli []T) (r, m T, err error) {
+	br := make([]*big.Int, len(residues))
+	for i, v := range residues {
+		br[i] = toBig(v)
+	}
+	bm := make([]*big.Int, len(moduli))
+	for i, v := range moduli {
+		bm[i] = toBig(v)
+	}
+	rb, mb, err := BigCRT(br, bm)
+	if err != nil {
+		return 0, 0, err
+	}
+	r, ok1 := fromBig[T](rb)
+	m, ok2 := fromBig[T](mb)
+	if !ok1 || !ok2 {
+		return 0, 0, ErrOverflow
+	}
+	return r, m, nil
+}
+
+// BigCRT is CRT for big.Ints. The moduli must be positive.
+func BigCRT(residues, moduli []*big.Int) (r, m *big.Int, err error) {
+	if len(residues) != len(moduli) {
+		return nil, nil, errors.New("numtheory: CRT needs one modulus per residue")
+	}
+	r, m = big.NewInt(0), big.NewInt(1)
+	for i, mi := range moduli {
+		if mi.Sign() <= 0 {
+			return nil, nil, errors.New("numtheory: CRT moduli must be positive")
+		}
+		ri := new(big.Int).Mod(residues[i], mi)
+
+		// Solve r + m*k = ri (mod mi) for k: m*k = ri-r (mod mi), which
+		// needs g = gcd(m, mi) to divide ri-r.
+		g, inv := new(big.Int), new(big.Int)
+		g.GCD(inv, nil, m, mi)
+		diff := new(big.Int).Sub(ri, r)
+		q, rem := new(big.Int).QuoRem(diff, g, new(big.Int))
+		if rem.Sign() != 0 {
+			return nil, nil, ErrNoSolution
+		}
+		step := new(big.Int).Quo(mi, g)
+		k := q.Mul(q, inv)
+		k.Mod(k, step)
+
+		r.Add(r, k.Mul(k, m))
+		m.Mul(m, step)
+		r.Mod(r, m)
+	}
+	return r, m, nil
+}
+
+// Isqrt returns the largest r with r*r <= n. It panics if n < 0.
+func Isqrt[T constraints.Integer](n T) T {
+	if n < 0 {
+		panic("numtheory: Isqrt of negative number")
+	}
+	if n < 2 {
+		return n
+	}
+	// Start from the float estimate and correct it, comparing by division
+	// so nothing overflows.
+	r := T(math.Sqrt(float64(n)))
+	for r > n/r {
+		r--
+	}
+	for r+1 <= n/(r+1) {
+		r++
+	}
+	return r
+}
+
+// IsSquare reports whether n is a perfect square.
+func IsSquare[T constraints.Integer](n T) bool {
+	if n < 0 {
+		return false
+	}
+	r := Isqrt(n)
+	return r*r == n
+}
@@ -0,0 +1,274 @@
+package numtheory
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestGCD(t *testing.T) {
+	tests := []struct{ a, b, want int64 }{
+		{0, 0, 0},
+		{0, 7, 7},
+		{12, 18, 6},
+		{-12, 18, 6},
+		{12, -18, 6},
+		{17, 5, 1},
+	}
+	for _, tc := range tests {
+		if got := GCD(tc.a, tc.b); got != tc.want {
+			t.Errorf("GCD(%d, %d) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+		g, x, y := ExtGCD(tc.a, tc.b)
+		if g != tc.want || tc.a*x+tc.b*y != g {
+			t.Errorf("ExtGCD(%d, %d) = %d, %d, %d: want gcd %d and %d*x + %d*y = gcd", tc.a, tc.b, g, x, y, tc.want, tc.a, tc.b)
+		}
+	}
+}
+
+func TestLCM(t *testing.T) {
+	tests := []struct {
+		vs   []int64
+		want int64
+		ok   bool
+	}{
+		{nil, 1, true},
+		{[]int64{4, 6}, 12, true},
+		{[]int64{-4, 6}, 12, true},
+		{[]int64{4, 0, 6}, 0, true},
+		{[]int64{2, 3, 5, 7, 11, 13}, 30030, true},
+		{[]int64{math.MaxInt64}, math.MaxInt64, true},
+		{[]int64{math.MaxInt64, 2}, 0, false},
+		{[]int64{1 << 62, 3}, 0, false},
+		{[]int64{1 << 62, 1 << 61}, 1 << 62, true},
+		{[]int64{math.MinInt64}, 0, false},
+		// The six day 8 periods, whose LCM needs 44 bits.
+		{[]int64{12169, 17263, 20093, 14999, 20659, 16697}, 12030780859469, true},
+	}
+	for _, tc := range tests {
+		got, ok := LCM(tc.vs...)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("LCM(%v) = %d, %v, want %d, %v", tc.vs, got, ok, tc.want, tc.ok)
+		}
+		if tc.ok && BigLCM(tc.vs...).Cmp(big.NewInt(tc.want)) != 0 {
+			t.Errorf("BigLCM(%v) = %v, want %d", tc.vs, BigLCM(tc.vs...), tc.want)
+		}
+	}
+
+	// Unsigned and narrow types overflow at their own limits.
+	if got, ok := LCM[uint8](15, 17); got != 255 || !ok {
+		t.Errorf("LCM[uint8](15, 17) = %d, %v, want 255, true", got, ok)
+	}
+	if _, ok := LCM[uint8](16, 17); ok {
+		t.Errorf("LCM[uint8](16, 17) fits")
+	}
+	if got, ok := LCM[uint64](1<<63, 1<<62); got != 1<<63 || !ok {
+		t.Errorf("LCM[uint64](2^63, 2^62) = %d, %v, want 2^63, true", got, ok)
+	}
+	if _, ok := LCM[uint64](1<<63, 3); ok {
+		t.Errorf("LCM[uint64](2^63, 3) fits")
+	}
+	want, _ := new(big.Int).SetString("27670116110564327424", 10) // 3 * 2^63
+	if got := BigLCM[uint64](1<<63, 3); got.Cmp(want) != 0 {
+		t.Errorf("BigLCM(2^63, 3) = %v, want %v", got, want)
+	}
+}
+
+func TestModular(t *testing.T) {
+	powTests := []struct{ base, exp, m, want int64 }{
+		{2, 10, 1000, 24},
+		{-2, 3, 7, 6},
+		{5, 0, 7, 1},
+		{5, 0, 1, 0},
+		{math.MaxInt64, math.MaxInt64, math.MaxInt64 - 1, 1},
+		// Fermat: a^(p-1) = 1 mod p, for a 62-bit prime.
+		{3, 4611686018427387846, 4611686018427387847, 1},
+	}
+	for _, tc := range powTests {
+		if got := ModPow(tc.base, tc.exp, tc.m); got != tc.want {
+			t.Errorf("ModPow(%d, %d, %d) = %d, want %d", tc.base, tc.exp, tc.m, got, tc.want)
+		}
+	}
+	if got := ModPow[uint64](math.MaxUint64, 2, math.MaxUint64-1); got != 1 {
+		t.Errorf("ModPow(2^64-1, 2, 2^64-2) = %d, want 1", got)
+	}
+
+	invTests := []struct {
+		a, m, want int64
+		ok         bool
+	}{
+		{3, 7, 5, true},
+		{-3, 7, 2, true},
+		{4, 8, 0, false},
+		{1, 1, 0, true},
+	}
+	for _, tc := range invTests {
+		got, ok := ModInverse(tc.a, tc.m)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("ModInverse(%d, %d) = %d, %v, want %d, %v", tc.a, tc.m, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestCRT(t *testing.T) {
+	tests := []struct {
+		residues, moduli []int64
+		r, m             int64
+		err              error
+	}{
+		{nil, nil, 0, 1, nil},
+		{[]int64{2, 3, 2}, []int64{3, 5, 7}, 23, 105, nil},
+		{[]int64{-1}, []int64{5}, 4, 5, nil},
+		{[]int64{12}, []int64{5}, 2, 5, nil},
+		// Not coprime, but consistent: the solution is mod the LCM.
+		{[]int64{2, 4}, []int64{4, 6}, 10, 12, nil},
+		{[]int64{3, 3, 3}, []int64{6, 10, 15}, 3, 30, nil},
+		{[]int64{0, 0}, []int64{12169, 12169 * 2}, 0, 24338, nil},
+		// Not coprime, and inconsistent.
+		{[]int64{1, 2}, []int64{4, 6}, 0, 0, ErrNoSolution},
+		{[]int64{1, 0}, []int64{2, 4}, 0, 0, ErrNoSolution},
+		// The combined modulus needs more than 63 bits.
+		{[]int64{1, 2}, []int64{1 << 62, 3}, 0, 0, ErrOverflow},
+		{[]int64{0, 0}, []int64{math.MaxInt64, math.MaxInt64 - 1}, 0, 0, ErrOverflow},
+	}
+	for _, tc := range tests {
+		r, m, err := CRT(tc.residues, tc.moduli)
+		if !errors.Is(err, tc.err) || r != tc.r || m != tc.m {
+			t.Errorf("CRT(%v, %v) = %d, %d, %v, want %d, %d, %v", tc.residues, tc.moduli, r, m, err, tc.r, tc.m, tc.err)
+		}
+	}
+
+	if _, _, err := CRT([]int64{1}, []int64{0}); err == nil {
+		t.Errorf("CRT with modulus 0: no error")
+	}
+	if _, _, err := CRT([]int64{1, 2}, []int64{3}); err == nil {
+		t.Errorf("CRT with unmatched residues: no error")
+	}
+
+	// What overflows int64 is fine as big.Ints.
+	r, m, err := BigCRT(
+		[]*big.Int{big.NewInt(1), big.NewInt(2)},
+		[]*big.Int{big.NewInt(1 << 62), big.NewInt(3)},
+	)
+	if err != nil || r.String() != "4611686018427387905" || m.String() != "13835058055282163712" {
+		t.Errorf("BigCRT(1 mod 2^62, 2 mod 3) = %v, %v, %v, want 4611686018427387905 mod 13835058055282163712", r, m, err)
+	}
+}
+
+func TestIsqrt(t *testing.T) {
+	tests := []struct{ n, want uint64 }{
+		{0, 0},
+		{1, 1},
+		{2, 1},
+		{3, 1},
+		{4, 2},
+		{99, 9},
+		{100, 10},
+		{1<<52 + 1, 1 << 26},
+		{(1<<32 - 1) * (1<<32 - 1), 1<<32 - 1},
+		{(1<<32-1)*(1<<32-1) - 1, 1<<32 - 2},
+		{(1<<32-1)*(1<<32-1) + 1, 1<<32 - 1},
+		// Large values where the float estimate is off by one.
+		{999999999999999999, 999999999},
+		{1000000000000000000, 1000000000},
+		{math.MaxUint64, 1<<32 - 1},
+		{math.MaxUint64 - 1, 1<<32 - 1},
+	}
+	for _, tc := range tests {
+		if got := Isqrt(tc.n); got != tc.want {
+			t.Errorf("Isqrt(%d) = %d, want %d", tc.n, got, tc.want)
+		}
+	}
+
+	if got := Isqrt[int64](math.MaxInt64); got != 3037000499 {
+		t.Errorf("Isqrt(MaxInt64) = %d, want 3037000499", got)
+	}
+	if got := Isqrt[uint8](255); got != 15 {
+		t.Errorf("Isqrt[uint8](255) = %d, want 15", got)
+	}
+	for _, n := range []int64{0, 1, 4, 3037000499 * 3037000499} {
+		if !IsSquare(n) {
+			t.Errorf("IsSquare(%d) = false", n)
+		}
+	}
+	for _, n := range []int64{-4, 2, 3037000499*3037000499 - 1, math.MaxInt64} {
+		if IsSquare(n) {
+			t.Errorf("IsSquare(%d) = true", n)
+		}
+	}
+}
+
+func TestIsPrime(t *testing.T) {
+	tests := []struct {
+		n    uint64
+		want bool
+	}{
+		{0, false},
+		{1, false},
+		{2, true},
+		{37, true},
+		{1369, false}, // 37^2
+		{1371, false},
+		{1373, true},
+		{561, false},        // Carmichael
+		{3215031751, false}, // strong pseudoprime to bases 2, 3, 5 and 7
+		{1<<61 - 1, true},
+		{1<<62 - 57, true},
+		{1<<63 - 25, true},
+		{1<<63 - 1, false},
+		{18446744073709551557, true}, // largest 64-bit prime
+		{18446744073709551559, false},
+		{math.MaxUint64, false},
+		{4294967291 * 4294967279, false}, // product of the two largest 32-bit primes
+		{3825123056546413051, false},     // strong pseudoprime to every prime base up to 23
+	}
+	for _, tc := range tests {
+		if got := IsPrime(tc.n); got != tc.want {
+			t.Errorf("IsPrime(%d) = %v, want %v", tc.n, got, tc.want)
+		}
+	}
+	if IsPrime[int64](-7) {
+		t.Errorf("IsPrime(-7) = true")
+	}
+}
+
+func TestFactorize(t *testing.T) {
+	tests := []struct {
+		n    uint64
+		want []Factor[uint64]
+	}{
+		{1, nil},
+		{2, []Factor[uint64]{{2, 1}}},
+		{360, []Factor[uint64]{{2, 3}, {3, 2}, {5, 1}}},
+		{1369, []Factor[uint64]{{37, 2}}},
+		{1681 * 1681, []Factor[uint64]{{41, 4}}},
+		{12030780859469, []Factor[uint64]{{43, 1}, {53, 1}, {59, 1}, {61, 1}, {71, 1}, {73, 1}, {283, 1}}},
+		{18446744073709551557, []Factor[uint64]{{18446744073709551557, 1}}},
+		{4294967291 * 4294967279, []Factor[uint64]{{4294967279, 1}, {4294967291, 1}}},
+		{4294967291 * 4294967291, []Factor[uint64]{{4294967291, 2}}},
+		{math.MaxUint64, []Factor[uint64]{{3, 1}, {5, 1}, {17, 1}, {257, 1}, {641, 1}, {65537, 1}, {6700417, 1}}},
+		{1 << 63, []Factor[uint64]{{2, 63}}},
+		{1000003 * 1000003 * 1000033, []Factor[uint64]{{1000003, 2}, {1000033, 1}}},
+	}
+	for _, tc := range tests {
+		got := Factorize(tc.n)
+		if len(got) != len(tc.want) {
+			t.Errorf("Factorize(%d) = %v, want %v", tc.n, got, tc.want)
+			continue
+		}
+		prod := uint64(1)
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("Factorize(%d) = %v, want %v", tc.n, got, tc.want)
+				break
+			}
+			for j := 0; j < got[i].Exp; j++ {
+				prod *= got[i].P
+			}
+		}
+		if prod != tc.n {
+			t.Errorf("Factorize(%d) multiplies back to %d", tc.n, prod)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package numtheory
+
+import (
+	"math/bits"
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// smallPrimes are tried by trial division before anything cleverer, and
+// serve as Miller-Rabin witnesses.
+var smallPrimes = []uint64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37}
+
+// IsPrime reports whether n is prime. It is exact for every 64-bit n.
+func IsPrime[T constraints.Integer](n T) bool {
+	if n < 2 {
+		return false
+	}
+	return isPrime(uint64(n))
+}
+
+func isPrime(n uint64) bool {
+	for _, p := range smallPrimes {
+		if n%p == 0 {
+			return n == p
+		}
+	}
+	if n < 37*37 {
+		return true
+	}
+
+	// Miller-Rabin, with witnesses that are known to be enough below 2^64.
+	d := n - 1
+	s := bits.TrailingZeros64(d)
+	d >>= s
+	for _, a := range smallPrimes {
+		x := powMod(a, d, n)
+		if x == 1 || x == n-1 {
+			continue
+		}
+		composite := true
+		for i := 1; i < s; i++ {
+			x = mulMod(x, x, n)
+			if x == n-1 {
+				composite = false
+				break
+			}
+		}
+		if composite {
+			return false
+		}
+	}
+	return true
+}
+
+// Factor is a prime factor and how many times it divides a number.
+type Factor[T constraints.Integer] struct {
+	P   T
+	Exp int
+}
+
+// Factorize returns the prime factors of n in increasing order. It panics
+// unless n > 0; Factorize(1) is empty.
+func Factorize[T constraints.Integer](n T) []Factor[T] {
+	if n <= 0 {
+		panic("numtheory: Factorize needs n > 0")
+	}
+	var primes []uint64
+	m := uint64(n)
+	for _, p := range smallPrimes {
+		for m%p == 0 {
+			primes = append(primes, p)
+			m /= p
+		}
+	}
+	primes = append(primes, splitPrimes(m)...)
+	sort.Slice(primes, func(i, j int) bool { return primes[i] < primes[j] })
+
+	var res []Factor[T]
+	for _, p := range primes {
+		if len(res) > 0 && uint64(res[len(res)-1].P) == p {
+			res[len(res)-1].Exp++
+		} else {
+			res = append(res, Factor[T]{T(p), 1})
+		}
+	}
+	return res
+}
+
+// splitPrimes returns the prime factors of n, which has no factor in
+// smallPrimes, with repeats.
+func splitPrimes(n uint64) []uint64 {
+	switch {
+	case n == 1:
+		return nil
+	case isPrime(n):
+		return []uint64{n}
+	}
+	d := pollardRho(n)
+	return append(splitPrimes(d), splitPrimes(n/d)...)
+}
+
+// pollardRho returns a non-trivial factor of the odd composite n, using
+// Brent's cycle finding.
+func pollardRho(n uint64) uint64 {
+	if r := Isqrt(n); r*r == n {
+		return r
+	}
+	for c := uint64(1); ; c++ {
+		f := func(x uint64) uint64 {
+			r := mulMod(x, x, n) + c
+			if r < c || r >= n {
+				r -= n // wrapping, if the sum did
+			}
+			return r
+		}
+		x, y, d := uint64(2), uint64(2), uint64(1)
+		for power, lam := 1, 1; d == 1; lam++ {
+			if power == lam {
+				x, power, lam = y, power*2, 0
+			}
+			y = f(y)
+			if x > y {
+				d = GCD(x-y, n)
+			} else {
+				d = GCD(y-x, n)
+			}
+		}
+		if d != n {
+			return d
+		}
+	}
+}
@@ -0,0 +1,404 @@
+// Package parse provides parser combinators for describing puzzle input
+// grammars declaratively.
+//
+// A Parser consumes a prefix of the input and returns a value. Parsers are
+// combined with Seq2, Alt, Many, SepBy, Section and friends, and run over a
+// whole input with Run. Alternatives backtrack freely: a parser that fails
+// leaves the input where it was. When parsing fails, the error points at the
+// furthest position any parser reached, and lists what was expected there.
+package parse
+
+import (
+	"aoc23/lib"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// State is a position in the input. It is a value, so keeping an old State
+// is all it takes to backtrack.
+type State struct {
+	src       string
+	off       int
+	line, col int // 1-based, col counted in runes
+	furthest  *failure
+}
+
+// Pos returns the line and column of the state.
+func (st State) Pos() lib.Pos {
+	return lib.Pos{Line: st.line, Column: st.col}
+}
+
+// rest returns the input not yet consumed.
+func (st State) rest() string {
+	return st.src[st.off:]
+}
+
+// advance returns the state n bytes further on.
+func (st State) advance(n int) State {
+	for _, r := range st.src[st.off : st.off+n] {
+		if r == '\n' {
+			st.line++
+			st.col = 1
+		} else {
+			st.col++
+		}
+	}
+	st.off += n
+	return st
+}
+
+// failure records what was expected at a position.
+type failure struct {
+	st       State
+	expected []string
+	msg      string // overrides expected, for errors other than a mismatch
+}
+
+func (f *failure) Error() string {
+	if f.msg != "" {
+		return f.msg
+	}
+	var exp []string
+	seen := make(map[string]bool)
+	for _, e := range f.expected {
+		if !seen[e] {
+			seen[e] = true
+			exp = append(exp, e)
+		}
+	}
+	sort.Strings(exp)
+	return "expected " + strings.Join(exp, " or ") + ", found " + found(f.st.rest())
+}
+
+// fail returns a failure at st, noting it if it is the furthest so far.
+func fail(st State, expected ...string) error {
+	return record(&failure{st: st, expected: expected})
+}
+
+// failMsg returns a failure at st with a custom message.
+func failMsg(st State, msgfmt string, args ...any) error {
+	return record(&failure{st: st, msg: fmt.Sprintf(msgfmt, args...)})
+}
+
+func record(f *failure) error {
+	best := f.st.furthest
+	switch {
+	case best.st.off < f.st.off || (best.st.off == f.st.off && f.msg != "" && best.msg == ""):
+		*best = *f
+	case best.st.off == f.st.off && best.msg == "":
+		best.expected = append(best.expected, f.expected...)
+	}
+	return f
+}
+
+// Parser consumes a prefix of the input starting at st, returning the
+// parsed value and the state after it.
+type Parser[T any] func(st State) (T, State, error)
+
+// Run parses the whole of src with p.
+func Run[T any](p Parser[T], src string) (T, error) {
+	var zero T
+	st := State{src: src, line: 1, col: 1, furthest: &failure{}}
+	st.furthest.st = st
+	v, end, err := p(st)
+	if err == nil && end.off < len(src) {
+		err = fail(end, "end of input")
+	}
+	if err != nil {
+		f := st.furthest
+		return zero, &lib.ParseError{Pos: f.st.Pos(), Err: errors.New(f.Error())}
+	}
+	return v, nil
+}
+
+// found describes the input at a failure.
+func found(rest string) string {
+	switch {
+	case rest == "":
+		return "end of input"
+	case rest[0] == '\n' || strings.HasPrefix(rest, "\r\n"):
+		return "end of line"
+	case rest[0] == ' ' || rest[0] == '\t':
+		return "space"
+	}
+	end := strings.IndexFunc(rest, unicode.IsSpace)
+	if end < 0 {
+		end = len(rest)
+	}
+	return strconv.Quote(rest[:end])
+}
+
+// Literal matches s exactly.
+func Literal(s string) Parser[string] {
+	return func(st State) (string, State, error) {
+		if !strings.HasPrefix(st.rest(), s) {
+			return "", st, fail(st, strconv.Quote(s))
+		}
+		return s, st.advance(len(s)), nil
+	}
+}
+
+// While matches one or more runes satisfying pred. name describes them in
+// errors.
+func While(name string, pred func(r rune) bool) Parser[string] {
+	return func(st State) (string, State, error) {
+		rest := st.rest()
+		n := 0
+		for n < len(rest) {
+			r, size := utf8.DecodeRuneInString(rest[n:])
+			if !pred(r) {
+				break
+			}
+			n += size
+		}
+		if n == 0 {
+			return "", st, fail(st, name)
+		}
+		return rest[:n], st.advance(n), nil
+	}
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Word matches a run of letters, digits and underscores.
+func Word() Parser[string] {
+	return While("word", isWordRune)
+}
+
+// Spaces matches one or more spaces or tabs.
+func Spaces() Parser[string] {
+	return While("space", func(r rune) bool { return r == ' ' || r == '\t' })
+}
+
+// Int matches a base 10 int, with an optional leading '-'.
+func Int() Parser[int] {
+	digits := While("integer", lib.IsNum)
+	return func(st State) (int, State, error) {
+		start := st
+		if strings.HasPrefix(st.rest(), "-") {
+			st = st.advance(1)
+		}
+		_, end, err := digits(st)
+		if err != nil {
+			return 0, start, fail(start, "integer")
+		}
+		text := start.src[start.off:end.off]
+		n, err := strconv.Atoi(text)
+		if err != nil {
+			return 0, start, failMsg(start, "integer %s out of range", text)
+		}
+		return n, end, nil
+	}
+}
+
+// Newline matches the end of a line.
+func Newline() Parser[string] {
+	return func(st State) (string, State, error) {
+		for _, nl := range []string{"\n", "\r\n"} {
+			if strings.HasPrefix(st.rest(), nl) {
+				return nl, st.advance(len(nl)), nil
+			}
+		}
+		return "", st, fail(st, "end of line")
+	}
+}
+
+// EOF matches the end of the input.
+func EOF() Parser[struct{}] {
+	return func(st State) (struct{}, State, error) {
+		if st.off < len(st.src) {
+			return struct{}{}, st, fail(st, "end of input")
+		}
+		return struct{}{}, st, nil
+	}
+}
+
+// LineEnd matches the end of a line, or of the input.
+func LineEnd() Parser[struct{}] {
+	return Alt(Map(Newline(), func(string) struct{} { return struct{}{} }), EOF())
+}
+
+// Map converts the result of p with f.
+func Map[T, R any](p Parser[T], f func(T) R) Parser[R] {
+	return func(st State) (R, State, error) {
+		v, end, err := p(st)
+		if err != nil {
+			var zero R
+			return zero, st, err
+		}
+		return f(v), end, nil
+	}
+}
+
+// Value matches p, returning v instead of its result.
+func Value[T, R any](p Parser[T], v R) Parser[R] {
+	return Map(p, func(T) R { return v })
+}
+
+// Seq matches each of ps in turn.
+func Seq[T any](ps ...Parser[T]) Parser[[]T] {
+	return func(st State) ([]T, State, error) {
+		res := make([]T, 0, len(ps))
+		cur := st
+		for _, p := range ps {
+			v, next, err := p(cur)
+			if err != nil {
+				return nil, st, err
+			}
+			res = append(res, v)
+			cur = next
+		}
+		return res, cur, nil
+	}
+}
+
+// Seq2 matches a then b, combining their results with f.
+func Seq2[A, B, R any](a Parser[A], b Parser[B], f func(A, B) R) Parser[R] {
+	return func(st State) (R, State, error) {
+		var zero R
+		va, next, err := a(st)
+		if err != nil {
+			return zero, st, err
+		}
+		vb, next, err := b(next)
+		if err != nil {
+			return zero, st, err
+		}
+		return f(va, vb), next, nil
+	}
+}
+
+// Seq3 matches a, b then c, combining their results with f.
+func Seq3[A, B, C, R any](a Parser[A], b Parser[B], c Parser[C], f func(A, B, C) R) Parser[R] {
+	ab := Seq2(a, b, func(va A, vb B) func(C) R {
+		return func(vc C) R { return f(va, vb, vc) }
+	})
+	return Seq2(ab, c, func(g func(C) R, vc C) R { return g(vc) })
+}
+
+// Left matches a then b, keeping a's result.
+func Left[A, B any](a Parser[A], b Parser[B]) Parser[A] {
+	return Seq2(a, b, func(va A, _ B) A { return va })
+}
+
+// Right matches a then b, keeping b's result.
+func Right[A, B any](a Parser[A], b Parser[B]) Parser[B] {
+	return Seq2(a, b, func(_ A, vb B) B { return vb })
+}
+
+// Alt tries each of ps in turn from the same position, returning the first
+// match.
+func Alt[T any](ps ...Parser[T]) Parser[T] {
+	return func(st State) (T, State, error) {
+		var zero T
+		var err error
+		for _, p := range ps {
+			var v T
+			var end State
+			if v, end, err = p(st); err == nil {
+				return v, end, nil
+			}
+		}
+		return zero, st, err
+	}
+}
+
+// Opt matches p if it can, returning def otherwise.
+func Opt[T any](p Parser[T], def T) Parser[T] {
+	return func(st State) (T, State, error) {
+		if v, end, err := p(st); err == nil {
+			return v, end, nil
+		}
+		return def, st, nil
+	}
+}
+
+// Many matches p as many times as possible, including none.
+func Many[T any](p Parser[T]) Parser[[]T] {
+	return func(st State) ([]T, State, error) {
+		var res []T
+		for {
+			v, next, err := p(st)
+			if err != nil || next.off == st.off {
+				return res, st, nil
+			}
+			res = append(res, v)
+			st = next
+		}
+	}
+}
+
+// Many1 matches p one or more times.
+func Many1[T any](p Parser[T]) Parser[[]T] {
+	return Seq2(p, Many(p), func(first T, rest []T) []T {
+		return append([]T{first}, rest...)
+	})
+}
+
+// SepBy matches zero or more p separated by sep.
+func SepBy[T, S any](p Parser[T], sep Parser[S]) Parser[[]T] {
+	return Opt(SepBy1(p, sep), nil)
+}
+
+// SepBy1 matches one or more p separated by sep.
+func SepBy1[T, S any](p Parser[T], sep Parser[S]) Parser[[]T] {
+	return Seq2(p, Many(Right(sep, p)), func(first T, rest []T) []T {
+		return append([]T{first}, rest...)
+	})
+}
+
+// Titled is the result of Section.
+type Titled[H, T any] struct {
+	Header H
+	Items  []T
+}
+
+// Section matches a header line followed by one item per line, up to a blank
+// line or the end of the input. The blank line is not consumed.
+func Section[H, T any](header Parser[H], item Parser[T]) Parser[Titled[H, T]] {
+	items := Many(Left(item, LineEnd()))
+	return Seq2(Left(header, LineEnd()), items, func(h H, ts []T) Titled[H, T] {
+		return Titled[H, T]{h, ts}
+	})
+}
+
+// Blocks matches zero or more p separated by blank lines.
+func Blocks[T any](p Parser[T]) Parser[[]T] {
+	return Left(SepBy(p, Many1(Newline())), Many(Newline()))
+}
+
+// Located is a value and where it was found.
+type Located[T any] struct {
+	Pos   lib.Pos
+	Value T
+}
+
+// At matches p, noting where its match started.
+func At[T any](p Parser[T]) Parser[Located[T]] {
+	return func(st State) (Located[T], State, error) {
+		v, end, err := p(st)
+		if err != nil {
+			return Located[T]{}, st, err
+		}
+		return Located[T]{st.Pos(), v}, end, nil
+	}
+}
+
+// Lazy defers building a parser until it is first used, for recursive
+// grammars.
+func Lazy[T any](build func() Parser[T]) Parser[T] {
+	var p Parser[T]
+	return func(st State) (T, State, error) {
+		if p == nil {
+			p = build()
+		}
+		return p(st)
+	}
+}
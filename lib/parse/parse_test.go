@@ -0,0 +1,130 @@
+package parse
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestPrimitives(t *testing.T) {
+	word := Left(Word(), EOF())
+	if got, err := Run(word, "abc_1é"); err != nil || got != "abc_1é" {
+		t.Errorf("Word = %q, %v", got, err)
+	}
+
+	ints := SepBy(Int(), Spaces())
+	got, err := Run(ints, "1 -2\t30  -0")
+	if want := []int{1, -2, 30, 0}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("SepBy(Int) = %v, %v, want %v", got, err, want)
+	}
+	if got, err := Run(ints, ""); err != nil || len(got) != 0 {
+		t.Errorf("SepBy(Int) on empty input = %v, %v", got, err)
+	}
+
+	lines := Many(Left(Word(), Newline()))
+	if got, err := Run(lines, "a\r\nb\n"); err != nil || !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Newline = %v, %v", got, err)
+	}
+
+	turn := Alt(Value(Literal("L"), 0), Value(Literal("R"), 1))
+	if got, err := Run(Many1(turn), "LRRL"); err != nil || !reflect.DeepEqual(got, []int{0, 1, 1, 0}) {
+		t.Errorf("Many1(Alt) = %v, %v", got, err)
+	}
+	if got, err := Run(Seq(Literal("a"), Opt(Literal("b"), "-"), Literal("c")), "ac"); err != nil || !reflect.DeepEqual(got, []string{"a", "-", "c"}) {
+		t.Errorf("Opt = %v, %v", got, err)
+	}
+}
+
+func TestBacktracking(t *testing.T) {
+	// The first alternative consumes "ab" before failing, so the second
+	// must start again from the beginning.
+	p := Alt(Seq(Literal("a"), Literal("b"), Literal("c")), Seq(Literal("a"), Literal("b"), Literal("d")))
+	if got, err := Run(p, "abd"); err != nil || !reflect.DeepEqual(got, []string{"a", "b", "d"}) {
+		t.Errorf("Alt = %v, %v", got, err)
+	}
+
+	// Many stops before a partial match of its item.
+	pair := Seq2(Int(), Right(Literal(","), Int()), func(a, b int) [2]int { return [2]int{a, b} })
+	items := Left(Many(Left(pair, Literal(";"))), Literal("1,x"))
+	got, err := Run(items, "1,2;3,4;1,x")
+	if want := [][2]int{{1, 2}, {3, 4}}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Many = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestSections(t *testing.T) {
+	header := Left(Word(), Literal(":"))
+	section := At(Section(header, SepBy1(Int(), Spaces())))
+	src := "\n\nfirst:\n1 2\n3\n\n\nsecond:\n\nthird:\n4\n\n"
+	got, err := Run(Right(Many(Newline()), Blocks(section)), src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb []string
+	for _, s := range got {
+		sb = append(sb, fmt.Sprintf("%s@%v=%v", s.Value.Header, s.Pos, s.Value.Items))
+	}
+	want := []string{
+		"first@line 3:1=[[1 2] [3]]",
+		"second@line 8:1=[]",
+		"third@line 10:1=[[4]]",
+	}
+	if !reflect.DeepEqual(sb, want) {
+		t.Errorf("Blocks = %q, want %q", sb, want)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	name := Word()
+	node := Seq3(name, Right(Literal(" = ("), name), Left(Right(Literal(", "), name), Literal(")")),
+		func(n, l, r string) [3]string { return [3]string{n, l, r} })
+	nodes := Many(Left(node, LineEnd()))
+
+	tests := []struct {
+		name string
+		p    Parser[any]
+		src  string
+		want string
+	}{
+		{"literal", anyOf(Seq(Literal("a"), Alt(Literal("b"), Literal("c")))), "ad", `line 1:2: expected "b" or "c", found "d"`},
+		{"furthest", anyOf(nodes), "AAA = (BBB, CCC)\nBBB = (DDD CCC)\n", `line 2:11: expected ", ", found space`},
+		{"end of line", anyOf(nodes), "AAA = (BBB, CCC)\nBBB = (DDD,\n", `line 2:11: expected ", ", found ","`},
+		{"trailing", anyOf(SepBy(Int(), Spaces())), "1 2 x", `line 1:5: expected integer, found "x"`},
+		{"end of input", anyOf(Seq(Literal("ab"), Literal("c"))), "ab", `line 1:3: expected "c", found end of input`},
+		{"overflow", anyOf(Int()), "99999999999999999999", `line 1:1: integer 99999999999999999999 out of range`},
+	}
+	for _, tc := range tests {
+		_, err := Run(tc.p, tc.src)
+		if err == nil || err.Error() != tc.want {
+			t.Errorf("%s: got %v, want %s", tc.name, err, tc.want)
+		}
+	}
+}
+
+// anyOf erases the result type of p, so parsers of different types can share
+// a table.
+func anyOf[T any](p Parser[T]) Parser[any] {
+	return Map(p, func(v T) any { return v })
+}
+
+func TestLazy(t *testing.T) {
+	// Nested lists: "[1,[2,3],[]]" sums to 6.
+	var list Parser[int]
+	list = Lazy(func() Parser[int] {
+		elem := Alt(Int(), list)
+		items := SepBy(elem, Literal(","))
+		return Right(Literal("["), Left(Map(items, func(ns []int) int {
+			sum := 0
+			for _, n := range ns {
+				sum += n
+			}
+			return sum
+		}), Literal("]")))
+	})
+	if got, err := Run(list, "[1,[2,3],[]]"); err != nil || got != 6 {
+		t.Errorf("Lazy = %d, %v, want 6", got, err)
+	}
+	if _, err := Run(list, "[1,[2,3]"); err == nil {
+		t.Errorf("unclosed list: no error")
+	}
+}
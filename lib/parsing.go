@@ -0,0 +1,183 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrSyntax is wrapped by ParseErrors for malformed numbers.
+	ErrSyntax = errors.New("invalid number")
+
+	// ErrOverflow is wrapped by ParseErrors for numbers that do not fit the
+	// requested size.
+	ErrOverflow = errors.New("number out of range")
+)
+
+// Pos locates a token in the input. Line and Column are 1-based, and zero
+// when unknown.
+type Pos struct {
+	Source string // input name, e.g. a file name
+	Line   int
+	Column int // counted in runes, not bytes, from the start of the line
+}
+
+// String formats the position as "source:line:col", or "line L:col" if the
+// source is not known, leaving out whatever else is unknown.
+func (p Pos) String() string {
+	var parts []string
+	if p.Source != "" {
+		parts = append(parts, p.Source)
+	}
+	if p.Line > 0 {
+		line := strconv.Itoa(p.Line)
+		if p.Source == "" {
+			line = "line " + line
+		}
+		parts = append(parts, line)
+		if p.Column > 0 {
+			parts = append(parts, strconv.Itoa(p.Column))
+		}
+	}
+	return strings.Join(parts, ":")
+}
+
+// Offset returns the position n columns (runes) further along the line.
+func (p Pos) Offset(n int) Pos {
+	if p.Column == 0 {
+		p.Column = 1
+	}
+	p.Column += n
+	return p
+}
+
+// Errorf returns a ParseError for token at p.
+func (p Pos) Errorf(token string, msgfmt string, args ...any) *ParseError {
+	return &ParseError{Pos: p, Token: token, Err: fmt.Errorf(msgfmt, args...)}
+}
+
+// ParseError describes bad input, and where it was found.
+type ParseError struct {
+	Pos
+	Token string // the offending text, if any
+	Err   error
+}
+
+func (e *ParseError) Error() string {
+	var sb strings.Builder
+	if pos := e.Pos.String(); pos != "" {
+		sb.WriteString(pos + ": ")
+	}
+	if e.Token != "" {
+		fmt.Fprintf(&sb, "%q: ", e.Token)
+	}
+	sb.WriteString(e.Err.Error())
+	return sb.String()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// WithSource sets the source name of every ParseError in err that does not
+// have one yet, and returns err.
+func WithSource(err error, source string) error {
+	var pe *ParseError
+	if errors.As(err, &pe) && pe.Source == "" {
+		pe.Source = source
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			WithSource(e, source)
+		}
+	}
+	return err
+}
+
+// Field is a whitespace-separated token and its 1-based column, counted in
+// runes like Pos.Column.
+type Field struct {
+	Text string
+	Col  int
+}
+
+// Fields splits line around runs of whitespace, like strings.Fields, noting
+// where each field starts.
+func Fields(line string) []Field {
+	var res []Field
+	start, startCol, col := -1, 0, 1
+	for i, r := range line {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				res = append(res, Field{line[start:i], startCol})
+				start = -1
+			}
+		} else if start < 0 {
+			start, startCol = i, col
+		}
+		col++
+	}
+	if start >= 0 {
+		res = append(res, Field{line[start:], startCol})
+	}
+	return res
+}
+
+func numError(p Pos, token string, err error) *ParseError {
+	if errors.Is(err, strconv.ErrRange) {
+		return &ParseError{Pos: p, Token: token, Err: ErrOverflow}
+	}
+	return &ParseError{Pos: p, Token: token, Err: ErrSyntax}
+}
+
+// ParseInt parses a base 10 int found at p.
+func ParseInt(p Pos, token string) (int, error) {
+	n, err := strconv.ParseInt(token, 10, strconv.IntSize)
+	if err != nil {
+		return 0, numError(p, token, err)
+	}
+	return int(n), nil
+}
+
+// ParseInt64 parses a base 10 int64 found at p.
+func ParseInt64(p Pos, token string) (int64, error) {
+	n, err := strconv.ParseInt(token, 10, 64)
+	if err != nil {
+		return 0, numError(p, token, err)
+	}
+	return n, nil
+}
+
+// ParseBigInt parses a base 10 integer of any size found at p.
+func ParseBigInt(p Pos, token string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(token, 10)
+	if !ok {
+		return nil, &ParseError{Pos: p, Token: token, Err: ErrSyntax}
+	}
+	return n, nil
+}
+
+// ParseDigit parses a single ASCII digit found at p.
+func ParseDigit(p Pos, r rune) (int, error) {
+	if r < '0' || r > '9' {
+		return 0, &ParseError{Pos: p, Token: string(r), Err: ErrSyntax}
+	}
+	return int(r - '0'), nil
+}
+
+// ParseInts parses the whitespace-separated ints in s, which starts at p.
+func ParseInts(p Pos, s string) ([]int, error) {
+	toks, err := IntFields.Scan(p, s)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]int, len(toks))
+	for i, t := range toks {
+		res[i] = t.Int
+	}
+	return res, nil
+}
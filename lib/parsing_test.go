@@ -0,0 +1,142 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestParseInt(t *testing.T) {
+	maxInt := strconv.Itoa(math.MaxInt)
+	minInt := strconv.Itoa(math.MinInt)
+	tests := []struct {
+		token string
+		want  int
+		err   error
+	}{
+		{"0", 0, nil},
+		{"-42", -42, nil},
+		{maxInt, math.MaxInt, nil},
+		{minInt, math.MinInt, nil},
+		{"9223372036854775808", 0, ErrOverflow},
+		{"-9223372036854775809", 0, ErrOverflow},
+		{"123456789012345678901234567890", 0, ErrOverflow},
+		{"", 0, ErrSyntax},
+		{"12a", 0, ErrSyntax},
+		{"0x10", 0, ErrSyntax},
+	}
+	if strconv.IntSize == 32 {
+		tests = append(tests, struct {
+			token string
+			want  int
+			err   error
+		}{"2147483648", 0, ErrOverflow})
+	}
+	for _, tc := range tests {
+		got, err := ParseInt(Pos{Line: 1, Column: 3}, tc.token)
+		if got != tc.want || !errors.Is(err, tc.err) || (err == nil) != (tc.err == nil) {
+			t.Errorf("ParseInt(%q) = %d, %v, want %d, %v", tc.token, got, err, tc.want, tc.err)
+		}
+	}
+}
+
+func TestParseInt64(t *testing.T) {
+	tests := []struct {
+		token string
+		want  int64
+		err   error
+	}{
+		{"9223372036854775807", math.MaxInt64, nil},
+		{"-9223372036854775808", math.MinInt64, nil},
+		{"9223372036854775808", 0, ErrOverflow},
+		{"-9223372036854775809", 0, ErrOverflow},
+		{"-", 0, ErrSyntax},
+	}
+	for _, tc := range tests {
+		got, err := ParseInt64(Pos{Line: 1, Column: 3}, tc.token)
+		if got != tc.want || !errors.Is(err, tc.err) || (err == nil) != (tc.err == nil) {
+			t.Errorf("ParseInt64(%q) = %d, %v, want %d, %v", tc.token, got, err, tc.want, tc.err)
+		}
+	}
+}
+
+func TestParseBigInt(t *testing.T) {
+	// Big integers never overflow, however long.
+	for _, token := range []string{"0", "-9223372036854775809", "123456789012345678901234567890123456789"} {
+		got, err := ParseBigInt(Pos{}, token)
+		if err != nil || got.String() != token {
+			t.Errorf("ParseBigInt(%q) = %v, %v, want %s", token, got, err, token)
+		}
+	}
+	for _, token := range []string{"", "1e9", "12 3"} {
+		if got, err := ParseBigInt(Pos{}, token); !errors.Is(err, ErrSyntax) {
+			t.Errorf("ParseBigInt(%q) = %v, %v, want %v", token, got, err, ErrSyntax)
+		}
+	}
+}
+
+func TestNumErrorPos(t *testing.T) {
+	_, err := ParseInt(Pos{Line: 2, Column: 5}, "99999999999999999999")
+	want := `line 2:5: "99999999999999999999": number out of range`
+	if err == nil || err.Error() != want {
+		t.Errorf("error = %v, want %s", err, want)
+	}
+}
+
+func TestWithSource(t *testing.T) {
+	first := Pos{Line: 1, Column: 2}.Errorf("x", "bad")
+	wrapped := Pos{Line: 2}.Errorf("y", "worse")
+	named := &ParseError{Pos: Pos{Source: "other.txt", Line: 3}, Err: ErrSyntax}
+	joined := errors.Join(first, fmt.Errorf("wrapped: %w", wrapped), named, errors.New("no position"))
+	if err := WithSource(joined, "input.txt"); err != joined {
+		t.Errorf("WithSource returned %v, want its argument", err)
+	}
+	for _, tc := range []struct {
+		pe   *ParseError
+		want string
+	}{
+		{first, "input.txt"},
+		{wrapped, "input.txt"},
+		{named, "other.txt"},
+	} {
+		if tc.pe.Source != tc.want {
+			t.Errorf("%v: source = %q, want %q", tc.pe, tc.pe.Source, tc.want)
+		}
+	}
+	want := "input.txt:1:2: \"x\": bad\nother.txt:3: invalid number\nno position"
+	if got := errors.Join(first, named, errors.New("no position")).Error(); got != want {
+		t.Errorf("error =\n%s\nwant\n%s", got, want)
+	}
+
+	// Joins nest, and a nil error stays nil.
+	inner := Pos{Line: 4}.Errorf("z", "deep")
+	WithSource(errors.Join(errors.Join(inner)), "nested.txt")
+	if inner.Source != "nested.txt" {
+		t.Errorf("nested join: source = %q, want nested.txt", inner.Source)
+	}
+	if err := WithSource(nil, "input.txt"); err != nil {
+		t.Errorf("WithSource(nil) = %v", err)
+	}
+}
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		line string
+		want []Field
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"a bc  d", []Field{{"a", 1}, {"bc", 3}, {"d", 7}}},
+		{"\tx\r\n", []Field{{"x", 2}}},
+		// Columns count runes, not bytes.
+		{"ü 1 ½ 2", []Field{{"ü", 1}, {"1", 3}, {"½", 5}, {"2", 7}}},
+	}
+	for _, tc := range tests {
+		if got := Fields(tc.line); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Fields(%q) = %v, want %v", tc.line, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package lib
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+	"time"
+)
+
+// Result is a single answer produced by a solver.
+type Result struct {
+	Day     int           `json:"day"`
+	Part    int           `json:"part"`
+	Answer  string        `json:"answer"` // "" if the part does not apply to the input
+	Elapsed time.Duration `json:"elapsed_ns"`
+	Parse   time.Duration `json:"parse_ns"` // time spent parsing the input, shared by both parts
+
+	Input     string `json:"input"`      // input file name, or "-" for stdin
+	InputHash string `json:"input_hash"` // truncated SHA-256 of the input contents
+}
+
+// InputHash identifies an input by its contents, so results computed from the
+// same data can be matched up regardless of file name.
+func InputHash(lines []string) string {
+	h := sha256.New()
+	for _, line := range lines {
+		io.WriteString(h, line)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// Run solves the given parts of the input (both, if none are given) and
+// returns one Result per part.
+func (d *Day) Run(input string, lines []string, parts ...int) ([]Result, error) {
+	if len(parts) == 0 {
+		parts = []int{1, 2}
+	}
+
+	startTime := time.Now()
+	parsed, err := d.Parse(lines)
+	parseTime := time.Since(startTime)
+	if err != nil {
+		return nil, fmt.Errorf("day %d: %w", d.Number, WithSource(err, input))
+	}
+	if d.Report != nil {
+		if err := d.Report(parsed); err != nil {
+			return nil, fmt.Errorf("day %d: %v", d.Number, err)
+		}
+	}
+
+	hash := InputHash(lines)
+	var res []Result
+	for _, part := range parts {
+		startTime := time.Now()
+		answer, err := d.Part(part)(parsed)
+		elapsed := time.Since(startTime)
+		if err != nil {
+			return nil, fmt.Errorf("day %d part %d: %v", d.Number, part, err)
+		}
+		res = append(res, Result{
+			Day:       d.Number,
+			Part:      part,
+			Answer:    answer,
+			Elapsed:   elapsed,
+			Parse:     parseTime,
+			Input:     input,
+			InputHash: hash,
+		})
+	}
+	return res, nil
+}
+
+// hashingIterator hashes the lines passing through it, so that streamed
+// inputs are identified the same way as InputHash.
+type hashingIterator struct {
+	TextIterator
+	h hash.Hash
+}
+
+func (hi *hashingIterator) NextLine() (string, error) {
+	line, err := hi.TextIterator.NextLine()
+	io.WriteString(hi.h, line)
+	return line, err
+}
+
+func (hi *hashingIterator) Lines() *LineIterator {
+	return NewLineIterator(context.Background(), hi)
+}
+
+func (hi *hashingIterator) LinesContext(ctx context.Context) *LineIterator {
+	return NewLineIterator(ctx, hi)
+}
+
+// RunStream is like Run, but reads the input from it in a single pass using
+// Day.Stream, stopping early if ctx is done. There is no separate parse step,
+// so each result's Elapsed covers the whole pass.
+func (d *Day) RunStream(ctx context.Context, input string, it TextIterator, parts ...int) ([]Result, error) {
+	if d.Stream == nil {
+		return nil, fmt.Errorf("day %d does not support streaming", d.Number)
+	}
+	if len(parts) == 0 {
+		parts = []int{1, 2}
+	}
+
+	hi := &hashingIterator{it, sha256.New()}
+	startTime := time.Now()
+	part1, part2, err := d.Stream(hi.LinesContext(ctx))
+	elapsed := time.Since(startTime)
+	if err != nil {
+		return nil, fmt.Errorf("day %d: %w", d.Number, WithSource(err, input))
+	}
+
+	hash := hex.EncodeToString(hi.h.Sum(nil))[:12]
+	var res []Result
+	for _, part := range parts {
+		answer := part1
+		if part == 2 {
+			answer = part2
+		}
+		res = append(res, Result{
+			Day:       d.Number,
+			Part:      part,
+			Answer:    answer,
+			Elapsed:   elapsed,
+			Input:     input,
+			InputHash: hash,
+		})
+	}
+	return res, nil
+}
+
+// ResultWriter formats results for output.
+type ResultWriter interface {
+	Write(r Result) error
+}
+
+// ResultFormats lists the formats accepted by NewResultWriter.
+var ResultFormats = []string{"text", "json", "tsv"}
+
+// NewResultWriter returns a ResultWriter for the named format:
+//
+//	text: human-readable "day 05 part 1: 35" lines
+//	json: one JSON object per line
+//	tsv:  tab-separated values, preceded by a header row
+func NewResultWriter(w io.Writer, format string) (ResultWriter, error) {
+	switch format {
+	case "text":
+		return textResultWriter{w}, nil
+	case "json":
+		return jsonResultWriter{json.NewEncoder(w)}, nil
+	case "tsv":
+		return &tsvResultWriter{w: w}, nil
+	}
+	return nil, fmt.Errorf("unknown result format %q (want one of %s)", format, strings.Join(ResultFormats, ", "))
+}
+
+type textResultWriter struct {
+	w io.Writer
+}
+
+func (trw textResultWriter) Write(r Result) error {
+	answer := r.Answer
+	if answer == "" {
+		answer = "n/a"
+	}
+	_, err := fmt.Fprintf(trw.w, "day %02d part %d: %s\n", r.Day, r.Part, answer)
+	return err
+}
+
+type jsonResultWriter struct {
+	enc *json.Encoder
+}
+
+func (jrw jsonResultWriter) Write(r Result) error {
+	return jrw.enc.Encode(r)
+}
+
+type tsvResultWriter struct {
+	w             io.Writer
+	headerWritten bool
+}
+
+func (trw *tsvResultWriter) Write(r Result) error {
+	if !trw.headerWritten {
+		if _, err := fmt.Fprintf(trw.w, "day\tpart\tanswer\telapsed_ns\tparse_ns\tinput\tinput_hash\n"); err != nil {
+			return err
+		}
+		trw.headerWritten = true
+	}
+	_, err := fmt.Fprintf(trw.w, "%d\t%d\t%s\t%d\t%d\t%s\t%s\n",
+		r.Day, r.Part, r.Answer, r.Elapsed.Nanoseconds(), r.Parse.Nanoseconds(), r.Input, r.InputHash)
+	return err
+}
@@ -0,0 +1,153 @@
+// Package sequence fits integer sequences with the polynomial of least
+// degree through their terms, so any term can be found exactly without
+// stepping through difference tables one term at a time.
+package sequence
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrNotPolynomial is returned by Fit for sequences whose differences do not
+// reach zero within the given terms.
+var ErrNotPolynomial = errors.New("sequence is not polynomial within its terms")
+
+// Poly is a polynomial taking integers to integers. It is held in Newton
+// form, p(n) = sum over k of diffs[k] * C(n, k), where diffs[k] is the k-th
+// forward difference of the sequence at n = 0.
+type Poly struct {
+	diffs []*big.Int // up to the degree, so the last is non-zero
+}
+
+// Fit returns the polynomial of least degree with p(i) = terms[i]. Its
+// degree is only trusted if the next row of differences confirms it: a
+// degree d polynomial needs at least d+2 terms, and sequences with too few
+// terms for that return ErrNotPolynomial.
+func Fit(terms []int) (*Poly, error) {
+	bs := make([]*big.Int, len(terms))
+	for i, t := range terms {
+		bs[i] = big.NewInt(int64(t))
+	}
+	return FitBig(bs)
+}
+
+// FitBig is Fit for terms of any size.
+func FitBig(terms []*big.Int) (*Poly, error) {
+	row := make([]*big.Int, len(terms))
+	for i, t := range terms {
+		row[i] = new(big.Int).Set(t)
+	}
+
+	p := &Poly{}
+	for len(row) > 0 {
+		zero := true
+		for _, v := range row {
+			zero = zero && v.Sign() == 0
+		}
+		if zero {
+			p.trim()
+			return p, nil
+		}
+		p.diffs = append(p.diffs, row[0])
+		for i := 0; i < len(row)-1; i++ {
+			row[i] = new(big.Int).Sub(row[i+1], row[i])
+		}
+		row = row[:len(row)-1]
+	}
+	return nil, fmt.Errorf("%w: %d terms leave no row of differences to confirm the degree", ErrNotPolynomial, len(terms))
+}
+
+// trim drops trailing zero differences.
+func (p *Poly) trim() {
+	for len(p.diffs) > 0 && p.diffs[len(p.diffs)-1].Sign() == 0 {
+		p.diffs = p.diffs[:len(p.diffs)-1]
+	}
+}
+
+// Degree returns the degree of the polynomial, or -1 if it is zero.
+func (p *Poly) Degree() int {
+	return len(p.diffs) - 1
+}
+
+// At returns p(n). n may be negative, to extend the sequence backwards.
+func (p *Poly) At(n int) *big.Int {
+	return p.AtBig(big.NewInt(int64(n)))
+}
+
+// AtBig returns p(n) for n of any size.
+func (p *Poly) AtBig(n *big.Int) *big.Int {
+	res := new(big.Int)
+	binom := big.NewInt(1) // C(n, k)
+	tmp := new(big.Int)
+	for k, d := range p.diffs {
+		res.Add(res, tmp.Mul(d, binom))
+		// C(n, k+1) = C(n, k) * (n-k) / (k+1), which divides exactly.
+		binom.Mul(binom, tmp.Sub(n, big.NewInt(int64(k))))
+		binom.Quo(binom, big.NewInt(int64(k+1)))
+	}
+	return res
+}
+
+// Coefficients returns the coefficients of p in powers of n, lowest first.
+func (p *Poly) Coefficients() []*big.Rat {
+	res := []*big.Rat{new(big.Rat)}
+	// falling is n(n-1)...(n-k+1) / k!, in powers of n.
+	falling := []*big.Rat{big.NewRat(1, 1)}
+	for k, d := range p.diffs {
+		for i, c := range falling {
+			if i == len(res) {
+				res = append(res, new(big.Rat))
+			}
+			res[i].Add(res[i], new(big.Rat).Mul(c, new(big.Rat).SetInt(d)))
+		}
+		// Multiply by (n - k) / (k+1).
+		next := make([]*big.Rat, len(falling)+1)
+		for i := range next {
+			next[i] = new(big.Rat)
+		}
+		scale := big.NewRat(1, int64(k+1))
+		for i, c := range falling {
+			next[i+1].Add(next[i+1], new(big.Rat).Mul(c, scale))
+			next[i].Sub(next[i], new(big.Rat).Mul(c, new(big.Rat).Mul(scale, big.NewRat(int64(k), 1))))
+		}
+		falling = next
+	}
+	return res
+}
+
+// String formats p in powers of n, e.g. "3/2n^2 - n + 4".
+func (p *Poly) String() string {
+	var sb strings.Builder
+	cs := p.Coefficients()
+	for i := len(cs) - 1; i >= 0; i-- {
+		c := cs[i]
+		if c.Sign() == 0 {
+			continue
+		}
+		switch {
+		case sb.Len() == 0 && c.Sign() < 0:
+			sb.WriteString("-")
+		case sb.Len() > 0 && c.Sign() < 0:
+			sb.WriteString(" - ")
+		case sb.Len() > 0:
+			sb.WriteString(" + ")
+		}
+		abs := new(big.Rat).Abs(c)
+		if !abs.IsInt() || abs.Num().Cmp(big.NewInt(1)) != 0 || i == 0 {
+			sb.WriteString(abs.RatString())
+		}
+		switch i {
+		case 0:
+		case 1:
+			sb.WriteString("n")
+		default:
+			fmt.Fprintf(&sb, "n^%d", i)
+		}
+	}
+	if sb.Len() == 0 {
+		return "0"
+	}
+	return sb.String()
+}
@@ -0,0 +1,91 @@
+package sequence
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		terms  []int
+		degree int
+		poly   string
+		next   int64 // p(len(terms))
+		prev   int64 // p(-1)
+	}{
+		// The day 9 sample histories.
+		{[]int{0, 3, 6, 9, 12, 15}, 1, "3n", 18, -3},
+		{[]int{1, 3, 6, 10, 15, 21}, 2, "1/2n^2 + 3/2n + 1", 28, 0},
+		{[]int{10, 13, 16, 21, 30, 45}, 3, "1/3n^3 - n^2 + 11/3n + 10", 68, 5},
+		{[]int{0, 0, 0}, -1, "0", 0, 0},
+		{[]int{7, 7}, 0, "7", 7, 7},
+		{[]int{4, 1, -2, -5}, 1, "-3n + 4", -8, 7},
+		{[]int{0, 1, 4, 9, 16}, 2, "n^2", 25, 1},
+		{[]int{-1, -2, -5, -10, -17}, 2, "-n^2 - 1", -26, -2},
+	}
+	for _, tc := range tests {
+		p, err := Fit(tc.terms)
+		if err != nil {
+			t.Errorf("Fit(%v): %v", tc.terms, err)
+			continue
+		}
+		if p.Degree() != tc.degree {
+			t.Errorf("Fit(%v).Degree() = %d, want %d", tc.terms, p.Degree(), tc.degree)
+		}
+		if got := p.String(); got != tc.poly {
+			t.Errorf("Fit(%v) = %s, want %s", tc.terms, got, tc.poly)
+		}
+		for i, want := range tc.terms {
+			if got := p.At(i); got.Cmp(big.NewInt(int64(want))) != 0 {
+				t.Errorf("Fit(%v).At(%d) = %v, want %d", tc.terms, i, got, want)
+			}
+		}
+		if got := p.At(len(tc.terms)); got.Int64() != tc.next {
+			t.Errorf("Fit(%v).At(%d) = %v, want %d", tc.terms, len(tc.terms), got, tc.next)
+		}
+		if got := p.At(-1); got.Int64() != tc.prev {
+			t.Errorf("Fit(%v).At(-1) = %v, want %d", tc.terms, got, tc.prev)
+		}
+	}
+}
+
+func TestFitNotPolynomial(t *testing.T) {
+	for _, terms := range [][]int{
+		nil,
+		{5},
+		{1, 2},
+		{1, 2, 4, 8, 16, 32}, // powers of two never settle
+	} {
+		if _, err := Fit(terms); !errors.Is(err, ErrNotPolynomial) {
+			t.Errorf("Fit(%v) = %v, want ErrNotPolynomial", terms, err)
+		}
+	}
+}
+
+func TestAtBig(t *testing.T) {
+	// p(n) = n^3 evaluated far beyond int64.
+	p, err := Fit([]int{0, 1, 8, 27, 64})
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, _ := new(big.Int).SetString("1000000000000000000000", 10)
+	want := new(big.Int).Exp(n, big.NewInt(3), nil)
+	if got := p.AtBig(n); got.Cmp(want) != 0 {
+		t.Errorf("AtBig(10^21) = %v, want %v", got, want)
+	}
+	neg := new(big.Int).Neg(n)
+	if got := p.AtBig(neg); got.Cmp(new(big.Int).Neg(want)) != 0 {
+		t.Errorf("AtBig(-10^21) = %v, want %v", got, new(big.Int).Neg(want))
+	}
+
+	// Terms beyond int64 fit too.
+	big1, _ := new(big.Int).SetString("100000000000000000000", 10)
+	q, err := FitBig([]*big.Int{big1, new(big.Int).Add(big1, big1), new(big.Int).Mul(big1, big.NewInt(3))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := q.At(9), new(big.Int).Mul(big1, big.NewInt(10)); got.Cmp(want) != 0 {
+		t.Errorf("FitBig: At(9) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,110 @@
+package lib
+
+import (
+	"flag"
+	"fmt"
+)
+
+// Day is a registered puzzle solution, split into a parse step and one step
+// per part so that each can be run and timed on its own.
+type Day struct {
+	Number int
+
+	// Parse turns the input lines, exactly as returned by GetInputAll, into
+	// whatever the parts operate on.
+	Parse func(lines []string) (any, error)
+
+	// Part1 and Part2 compute the answers from the parsed input. They must
+	// not depend on each other having run first. A part that does not apply
+	// to the given input (e.g. a part 2 sample fed to part 1) returns "".
+	Part1 func(input any) (string, error)
+	Part2 func(input any) (string, error)
+
+	// Report optionally writes extra analysis of the parsed input, as asked
+	// for by the day's flags, to stderr. aoc run calls it once per input,
+	// after parsing and outside the timed stages; verify and bench do not.
+	Report func(input any) error
+
+	// Stream optionally solves both parts in a single pass over the input,
+	// holding only what it needs rather than every line, for inputs too
+	// large to load. It returns lines.Err() if the iteration stops early.
+	Stream func(lines *LineIterator) (part1, part2 string, err error)
+
+	// Generate optionally returns n lines of random input drawn from seed,
+	// for benchmarking at sizes no real input reaches.
+	Generate func(n int, seed int64) []string
+
+	// Flags holds optional day-specific settings. The FlagSet name is used
+	// as a prefix when the flags are exposed on the command line.
+	Flags *flag.FlagSet
+}
+
+// ParseFunc adapts a typed parse function for use as Day.Parse.
+func ParseFunc[T any](f func(lines []string) (T, error)) func([]string) (any, error) {
+	return func(lines []string) (any, error) {
+		return f(lines)
+	}
+}
+
+// PartFunc adapts a typed part function for use as Day.Part1 or Day.Part2.
+func PartFunc[T any](f func(input T) (string, error)) func(any) (string, error) {
+	return func(input any) (string, error) {
+		return f(input.(T))
+	}
+}
+
+// ReportFunc adapts a typed report function for use as Day.Report.
+func ReportFunc[T any](f func(input T) error) func(any) error {
+	return func(input any) error {
+		return f(input.(T))
+	}
+}
+
+// Part returns the function solving part n.
+func (d *Day) Part(n int) func(input any) (string, error) {
+	if n == 1 {
+		return d.Part1
+	}
+	return d.Part2
+}
+
+// Solve parses the input and computes both parts.
+func (d *Day) Solve(lines []string) (part1, part2 string, err error) {
+	input, err := d.Parse(lines)
+	if err != nil {
+		return "", "", err
+	}
+	if part1, err = d.Part1(input); err != nil {
+		return "", "", err
+	}
+	if part2, err = d.Part2(input); err != nil {
+		return "", "", err
+	}
+	return part1, part2, nil
+}
+
+var days = make(map[int]*Day)
+
+// Register adds a day to the solver registry. It is meant to be called from
+// each day's init function, and panics if the day is already registered.
+func Register(d Day) {
+	if _, ok := days[d.Number]; ok {
+		panic(fmt.Sprintf("day %d registered twice", d.Number))
+	}
+	days[d.Number] = &d
+}
+
+// LookupDay returns the registered day with the given number.
+func LookupDay(n int) (*Day, bool) {
+	d, ok := days[n]
+	return d, ok
+}
+
+// Days returns all registered days, ordered by day number.
+func Days() []*Day {
+	res := make([]*Day, 0, len(days))
+	for _, n := range Keys(days) {
+		res = append(res, days[n])
+	}
+	return res
+}
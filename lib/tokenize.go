@@ -0,0 +1,187 @@
+package lib
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Span is a half-open [Start, End) range of offsets within a line.
+type Span struct {
+	Start, End int
+}
+
+// Len returns the number of offsets in the span.
+func (s Span) Len() int {
+	return s.End - s.Start
+}
+
+// NumberToken is a number found in a line of text.
+type NumberToken struct {
+	Text    string  // as written, including any sign and separators
+	Int     int     // the value, if not Decimal
+	Float   float64 // the value
+	Neg     bool    // whether the number has a leading '-'
+	Decimal bool    // whether the number has a fractional part
+	Pos     Pos     // position of the first rune
+	Bytes   Span    // byte offsets within the line
+	Runes   Span    // rune offsets within the line
+}
+
+// Context returns up to n runes of line either side of the token.
+func (t NumberToken) Context(line string, n int) (before, after string) {
+	rs := []rune(line)
+	before = string(rs[Max(0, t.Runes.Start-n):t.Runes.Start])
+	after = string(rs[t.Runes.End:Min(len(rs), t.Runes.End+n)])
+	return before, after
+}
+
+// Neighbor is a rune next to a token.
+type Neighbor struct {
+	Pos  Pos
+	Rune rune
+}
+
+// Neighbors returns the runes surrounding the token, including diagonally,
+// on the lines above and below it. lines holds every line of the input, with
+// the token's line at lines[t.Pos.Line-1].
+func (t NumberToken) Neighbors(lines []string) []Neighbor {
+	var res []Neighbor
+	for ln := t.Pos.Line - 1; ln <= t.Pos.Line+1; ln++ {
+		if ln < 1 || ln > len(lines) {
+			continue
+		}
+		rs := []rune(strings.TrimRight(lines[ln-1], "\r\n"))
+		for col := t.Runes.Start - 1; col <= t.Runes.End; col++ {
+			if col < 0 || col >= len(rs) || (ln == t.Pos.Line && col >= t.Runes.Start && col < t.Runes.End) {
+				continue
+			}
+			pos := t.Pos
+			pos.Line = ln
+			pos.Column += col - t.Runes.Start
+			res = append(res, Neighbor{pos, rs[col]})
+		}
+	}
+	return res
+}
+
+// NumberScanner finds the numbers in text.
+type NumberScanner struct {
+	// Signed counts a '-' or '+' directly before the digits as part of the
+	// number, unless it follows a letter or digit (as in "a-1" or "3-4").
+	Signed bool
+
+	// Decimals allows a fractional part, a '.' followed by digits.
+	Decimals bool
+
+	// Separators lists the runes allowed between two digits to group them,
+	// e.g. "_," for "1_000" and "1,000".
+	Separators string
+
+	// Strict reports anything but whitespace between the numbers as a
+	// ParseError, rather than skipping it.
+	Strict bool
+}
+
+var (
+	// Digits finds unsigned integers.
+	Digits = NumberScanner{}
+
+	// Integers finds signed integers.
+	Integers = NumberScanner{Signed: true}
+
+	// IntFields reads whitespace-separated signed integers.
+	IntFields = NumberScanner{Signed: true, Strict: true}
+)
+
+// Scan returns the numbers in line, which starts at p. Numbers too large for
+// an int are reported as a ParseError.
+func (ns NumberScanner) Scan(p Pos, line string) ([]NumberToken, error) {
+	if p.Column == 0 {
+		p.Column = 1
+	}
+	rs := []rune(line)
+	byteOff := make([]int, 0, len(rs)+1)
+	for i := range line {
+		byteOff = append(byteOff, i)
+	}
+	byteOff = append(byteOff, len(line))
+
+	isDigit := func(i int) bool {
+		return i >= 0 && i < len(rs) && IsNum(rs[i])
+	}
+	// badField reports the whitespace-separated field around i.
+	badField := func(i int) error {
+		start, end := i, i
+		for start > 0 && !unicode.IsSpace(rs[start-1]) {
+			start--
+		}
+		for end < len(rs) && !unicode.IsSpace(rs[end]) {
+			end++
+		}
+		return &ParseError{Pos: p.Offset(start), Token: string(rs[start:end]), Err: ErrSyntax}
+	}
+
+	var res []NumberToken
+	for i := 0; i < len(rs); {
+		start := i
+		neg := false
+		if ns.Signed && (rs[i] == '-' || rs[i] == '+') && isDigit(i+1) &&
+			(i == 0 || !(IsNum(rs[i-1]) || unicode.IsLetter(rs[i-1]))) {
+			neg = rs[i] == '-'
+			i++
+		}
+		if !isDigit(i) {
+			if ns.Strict && !unicode.IsSpace(rs[start]) {
+				return nil, badField(start)
+			}
+			i = start + 1
+			continue
+		}
+
+		// value is the number without separators, as strconv expects.
+		var value strings.Builder
+		if neg {
+			value.WriteRune('-')
+		}
+		for i < len(rs) {
+			if isDigit(i) {
+				value.WriteRune(rs[i])
+			} else if !(strings.ContainsRune(ns.Separators, rs[i]) && isDigit(i+1)) {
+				break
+			}
+			i++
+		}
+		decimal := ns.Decimals && i < len(rs) && rs[i] == '.' && isDigit(i+1)
+		if decimal {
+			value.WriteRune('.')
+			for i++; isDigit(i); i++ {
+				value.WriteRune(rs[i])
+			}
+		}
+
+		if ns.Strict && i < len(rs) && !unicode.IsSpace(rs[i]) {
+			return nil, badField(i)
+		}
+
+		tok := NumberToken{
+			Text:    string(rs[start:i]),
+			Neg:     neg,
+			Decimal: decimal,
+			Pos:     p.Offset(start),
+			Bytes:   Span{byteOff[start], byteOff[i]},
+			Runes:   Span{start, i},
+		}
+		var err error
+		if decimal {
+			tok.Float, err = strconv.ParseFloat(value.String(), 64)
+		} else if tok.Int, err = strconv.Atoi(value.String()); err == nil {
+			tok.Float = float64(tok.Int)
+		}
+		if err != nil {
+			return nil, numError(tok.Pos, tok.Text, err)
+		}
+		res = append(res, tok)
+	}
+	return res, nil
+}
@@ -0,0 +1,102 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNumberScanner(t *testing.T) {
+	tests := []struct {
+		name string
+		ns   NumberScanner
+		line string
+		want string // "text=value@col" for each token
+	}{
+		{"digits", Digits, "Game 12: 3 blue, 40 red", "12=12@6 3=3@10 40=40@18"},
+		{"digits ignore signs", Digits, "-3 +4", "3=3@2 4=4@5"},
+		{"integers", Integers, "0 3 -6 +9", "0=0@1 3=3@3 -6=-6@5 +9=9@8"},
+		{"ranges are not signs", Integers, "3-4 a-1 (-2)", "3=3@1 4=4@3 1=1@7 -2=-2@10"},
+		{"lone signs", Integers, "- + -x", ""},
+		{"separators", NumberScanner{Separators: "_,"}, "1_000,2 3,,4 5_", "1_000,2=10002@1 3=3@9 4=4@12 5=5@14"},
+		{"decimals", NumberScanner{Signed: true, Decimals: true}, "x=-1.5, y=2., z=.5", "-1.5=-1.5@3 2=2@11 5=5@18"},
+		{"runes", Digits, "é1 ½ 23", "1=1@2 23=23@6"},
+		{"strict", IntFields, "  1\t-2  30 ", "1=1@3 -2=-2@5 30=30@9"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			toks, err := tc.ns.Scan(Pos{Line: 1}, tc.line)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ""
+			for i, tok := range toks {
+				if i > 0 {
+					got += " "
+				}
+				if tok.Decimal {
+					got += fmt.Sprintf("%s=%g@%d", tok.Text, tok.Float, tok.Pos.Column)
+				} else {
+					got += fmt.Sprintf("%s=%d@%d", tok.Text, tok.Int, tok.Pos.Column)
+				}
+				if tok.Runes.Len() != len([]rune(tok.Text)) || tc.line[tok.Bytes.Start:tok.Bytes.End] != tok.Text {
+					t.Errorf("token %q has spans %v (runes) %v (bytes)", tok.Text, tok.Runes, tok.Bytes)
+				}
+			}
+			if got != tc.want {
+				t.Errorf("Scan(%q) = %s, want %s", tc.line, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNumberScannerErrors(t *testing.T) {
+	tests := []struct {
+		ns   NumberScanner
+		line string
+		want string
+		err  error
+	}{
+		{IntFields, "1 2x 3", `line 1:3: "2x": invalid number`, ErrSyntax},
+		{IntFields, "1 x2 3", `line 1:3: "x2": invalid number`, ErrSyntax},
+		{IntFields, "1 - 3", `line 1:3: "-": invalid number`, ErrSyntax},
+		{Digits, "a 99999999999999999999", `line 1:3: "99999999999999999999": number out of range`, ErrOverflow},
+		{Integers, "-9223372036854775809", `line 1:1: "-9223372036854775809": number out of range`, ErrOverflow},
+	}
+	for _, tc := range tests {
+		_, err := tc.ns.Scan(Pos{Line: 1}, tc.line)
+		if err == nil || err.Error() != tc.want || !errors.Is(err, tc.err) {
+			t.Errorf("Scan(%q) = %v, want %s", tc.line, err, tc.want)
+		}
+	}
+
+	toks, err := Integers.Scan(Pos{}, "-9223372036854775808")
+	if err != nil || len(toks) != 1 || toks[0].Int != -9223372036854775808 {
+		t.Errorf("Scan(MinInt64) = %v, %v", toks, err)
+	}
+}
+
+func TestNumberTokenContext(t *testing.T) {
+	lines := []string{
+		"467..114..\n",
+		"...*......\n",
+		"..35..633.\n",
+	}
+	toks, err := Digits.Scan(Pos{Line: 3}, lines[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, after := toks[0].Context(lines[2], 2)
+	if before != ".." || after != ".." {
+		t.Errorf("Context(2) = %q, %q, want \"..\", \"..\"", before, after)
+	}
+
+	var got string
+	for _, n := range toks[0].Neighbors(lines) {
+		got += fmt.Sprintf("%c@%d:%d ", n.Rune, n.Pos.Line, n.Pos.Column)
+	}
+	want := ".@2:2 .@2:3 *@2:4 .@2:5 .@3:2 .@3:5 "
+	if got != want {
+		t.Errorf("Neighbors = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,344 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The line layout of a struct is given by the `line` tag of a blank field,
+// with each {name} placeholder filled from the field of that name (compared
+// case-insensitively) or with that `line` tag:
+//
+//	type game struct {
+//		_      struct{} `line:"Game {id}: {rounds;sep=\";\"}"`
+//		ID     int
+//		Rounds []round
+//	}
+//
+// Fields may be ints, strings, slices of those, structs with their own
+// layout, or slices of such structs. Slices are split on whitespace, unless
+// a separator is given with the sep option. Whitespace in the layout matches
+// any run of whitespace in the line, and values are trimmed. Strings are
+// single words, so that trailing text is an error rather than part of the
+// last field.
+//
+// Errors name the value that failed by its path of placeholders, such as
+// "rounds[0].cubes[1].count".
+
+// linePattern is the compiled layout of a struct type.
+type linePattern struct {
+	layout  string
+	re      *regexp.Regexp
+	literal []string // literal text before each placeholder, and after the last
+	fields  []patternField
+}
+
+type patternField struct {
+	name  string // placeholder name
+	index int    // struct field index
+	sep   string // slice separator, "" for whitespace
+}
+
+var patterns sync.Map // reflect.Type -> *linePattern
+
+func patternFor(t reflect.Type) (*linePattern, error) {
+	if lp, ok := patterns.Load(t); ok {
+		return lp.(*linePattern), nil
+	}
+	lp, err := compilePattern(t)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(t, lp)
+	return lp, nil
+}
+
+func compilePattern(t reflect.Type) (*linePattern, error) {
+	var layout string
+	found := false
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.Name == "_" {
+			layout, found = f.Tag.Lookup("line")
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%v has no line layout", t)
+	}
+
+	lp := &linePattern{layout: layout}
+	var re strings.Builder
+	re.WriteString(`^`)
+	rest := layout
+	for {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			break
+		}
+		lit := rest[:open]
+		if open == 0 && len(lp.fields) > 0 {
+			return nil, fmt.Errorf("%v: layout %q has adjacent placeholders", t, layout)
+		}
+		lp.literal = append(lp.literal, lit)
+		re.WriteString(literalRegexp(lit))
+
+		pf, n, err := parsePlaceholder(rest[open:])
+		if err != nil {
+			return nil, fmt.Errorf("%v: layout %q: %v", t, layout, err)
+		}
+		pf.index = -1
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if name, ok := f.Tag.Lookup("line"); (ok && f.Name != "_" && name == pf.name) || strings.EqualFold(f.Name, pf.name) {
+				pf.index = i
+			}
+		}
+		if pf.index < 0 || !t.Field(pf.index).IsExported() {
+			return nil, fmt.Errorf("%v: no exported field for {%s}", t, pf.name)
+		}
+		lp.fields = append(lp.fields, pf)
+		re.WriteString(`(.*?)`)
+		rest = rest[open+n:]
+	}
+	lp.literal = append(lp.literal, rest)
+	re.WriteString(literalRegexp(rest))
+	re.WriteString(`$`)
+
+	var err error
+	if lp.re, err = regexp.Compile(re.String()); err != nil {
+		return nil, fmt.Errorf("%v: layout %q: %v", t, layout, err)
+	}
+	return lp, nil
+}
+
+// literalRegexp matches lit, with any run of whitespace in it matching any
+// run of whitespace in the line.
+func literalRegexp(lit string) string {
+	var sb strings.Builder
+	for i, part := range strings.FieldsFunc(lit, unicode.IsSpace) {
+		if i > 0 || strings.IndexFunc(lit, unicode.IsSpace) == 0 {
+			sb.WriteString(`\s+`)
+		}
+		sb.WriteString(regexp.QuoteMeta(part))
+	}
+	if lit != "" && unicode.IsSpace(rune(lit[len(lit)-1])) {
+		sb.WriteString(`\s+`)
+	}
+	return sb.String()
+}
+
+// parsePlaceholder reads a "{name}" or "{name;sep=...}" placeholder from the
+// start of s, returning its length.
+func parsePlaceholder(s string) (patternField, int, error) {
+	var pf patternField
+	end := strings.IndexAny(s, ";}")
+	if end < 0 {
+		return pf, 0, fmt.Errorf("unterminated placeholder")
+	}
+	pf.name = strings.TrimSpace(s[1:end])
+	if pf.name == "" {
+		return pf, 0, fmt.Errorf("empty placeholder")
+	}
+	if s[end] == '}' {
+		return pf, end + 1, nil
+	}
+
+	opt := s[end+1:]
+	if !strings.HasPrefix(opt, "sep=") {
+		return pf, 0, fmt.Errorf("{%s}: unknown option in %q", pf.name, opt)
+	}
+	opt = opt[len("sep="):]
+	n := len(s) - len(opt)
+	if quoted, err := strconv.QuotedPrefix(opt); err == nil {
+		pf.sep, _ = strconv.Unquote(quoted)
+		opt = opt[len(quoted):]
+		n += len(quoted)
+	} else {
+		end := strings.IndexByte(opt, '}')
+		if end < 0 {
+			return pf, 0, fmt.Errorf("{%s}: unterminated placeholder", pf.name)
+		}
+		pf.sep = opt[:end]
+		opt = opt[end:]
+		n += end
+	}
+	if !strings.HasPrefix(opt, "}") {
+		return pf, 0, fmt.Errorf("{%s}: unterminated placeholder", pf.name)
+	}
+	return pf, n + 1, nil
+}
+
+// Unmarshal fills the struct pointed to by v from line, following the
+// struct's line layout.
+func Unmarshal(line string, v any) error {
+	return UnmarshalAt(Pos{}, line, v)
+}
+
+// UnmarshalAt is like Unmarshal, for a line found at p. Errors are reported as
+// a ParseError naming the field that failed.
+func UnmarshalAt(p Pos, line string, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("unmarshal: want pointer to struct, got %T", v)
+	}
+	if p.Column == 0 {
+		p.Column = 1
+	}
+	return unmarshalValue(p, strings.TrimRight(line, "\r\n"), rv.Elem(), "", "")
+}
+
+// unmarshalValue fills v from s, found at p. path names v for errors, "" for
+// the whole line, and sep splits s if v is a slice.
+func unmarshalValue(p Pos, s string, v reflect.Value, path, sep string) error {
+	// Trim the value, keeping track of where it starts.
+	trimmed := strings.TrimLeftFunc(s, unicode.IsSpace)
+	p = p.Offset(utf8.RuneCountInString(s[:len(s)-len(trimmed)]))
+	s = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+
+	fail := func(err error) error {
+		if path != "" {
+			err = fmt.Errorf("%s: %w", path, err)
+		}
+		return &ParseError{Pos: p, Token: s, Err: err}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		if i := strings.IndexFunc(s, unicode.IsSpace); i >= 0 {
+			extra := strings.TrimLeftFunc(s[i:], unicode.IsSpace)
+			return &ParseError{
+				Pos:   p.Offset(utf8.RuneCountInString(s[:len(s)-len(extra)])),
+				Token: extra,
+				Err:   fmt.Errorf("%s: unexpected text after %q", path, s[:i]),
+			}
+		}
+		v.SetString(s)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return fail(numError(p, s, err).Err)
+		}
+		v.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return fail(numError(p, s, err).Err)
+		}
+		v.SetUint(n)
+
+	case reflect.Slice:
+		var parts []Field
+		if sep == "" {
+			parts = Fields(s)
+		} else if s != "" {
+			col := 1
+			for _, part := range strings.Split(s, sep) {
+				parts = append(parts, Field{part, col})
+				col += utf8.RuneCountInString(part) + utf8.RuneCountInString(sep)
+			}
+		}
+		res := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			elemPath := fmt.Sprintf("%s[%d]", path, i)
+			if err := unmarshalValue(p.Offset(part.Col-1), part.Text, res.Index(i), elemPath, ""); err != nil {
+				return err
+			}
+		}
+		v.Set(res)
+
+	case reflect.Struct:
+		lp, err := patternFor(v.Type())
+		if err != nil {
+			return err
+		}
+		m := lp.re.FindStringSubmatchIndex(s)
+		if m == nil {
+			return fail(fmt.Errorf("does not match %q", lp.layout))
+		}
+		for i, pf := range lp.fields {
+			lo, hi := m[2*i+2], m[2*i+3]
+			f := v.Field(pf.index)
+			fieldPath := pf.name
+			if path != "" {
+				fieldPath = path + "." + fieldPath
+			}
+			if err := unmarshalValue(p.Offset(utf8.RuneCountInString(s[:lo])), s[lo:hi], f, fieldPath, pf.sep); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return fail(fmt.Errorf("unsupported type %v", v.Type()))
+	}
+	return nil
+}
+
+// Marshal formats the struct v (or pointer to it) following its line
+// layout, such that Unmarshal reads it back. Slice elements are separated by
+// a space, or by their separator and a space.
+func Marshal(v any) (string, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return "", fmt.Errorf("marshal: want struct, got %T", v)
+	}
+	var sb strings.Builder
+	if err := marshalValue(&sb, rv, ""); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func marshalValue(sb *strings.Builder, v reflect.Value, sep string) error {
+	switch v.Kind() {
+	case reflect.String:
+		sb.WriteString(v.String())
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		sb.WriteString(strconv.FormatInt(v.Int(), 10))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		sb.WriteString(strconv.FormatUint(v.Uint(), 10))
+
+	case reflect.Slice:
+		// Values are trimmed when read, so a space after each separator
+		// reads back the same, and matches how puzzles are laid out.
+		if strings.TrimSpace(sep) == "" {
+			sep = " "
+		} else {
+			sep += " "
+		}
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				sb.WriteString(sep)
+			}
+			if err := marshalValue(sb, v.Index(i), ""); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Struct:
+		lp, err := patternFor(v.Type())
+		if err != nil {
+			return err
+		}
+		for i, pf := range lp.fields {
+			sb.WriteString(lp.literal[i])
+			if err := marshalValue(sb, v.Field(pf.index), pf.sep); err != nil {
+				return err
+			}
+		}
+		sb.WriteString(lp.literal[len(lp.literal)-1])
+
+	default:
+		return errors.New("marshal: unsupported type " + v.Type().String())
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package lib
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testGame struct {
+	_      struct{} `line:"Game {id}: {rounds;sep=\";\"}"`
+	ID     int
+	Rounds []testRound
+}
+
+type testRound struct {
+	_     struct{} `line:"{cubes;sep=\",\"}"`
+	Cubes []testCube
+}
+
+type testCube struct {
+	_      struct{} `line:"{count} {colour}"`
+	Count  int
+	Colour string
+}
+
+type testCard struct {
+	_       struct{} `line:"Card {id}: {winning} | {picks}"`
+	ID      int
+	Winning []int
+	Picks   []uint8
+}
+
+type testHand struct {
+	_     struct{} `line:"{cards} {bid}"`
+	Cards string
+	Bid   int
+}
+
+// TestRoundTrip checks that fixtures marshal to their line, and that the
+// line unmarshals back to the fixture.
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		line string
+		v    any
+	}{
+		{
+			"Game 1: 3 blue, 4 red; 1 red, 2 green",
+			&testGame{ID: 1, Rounds: []testRound{
+				{Cubes: []testCube{{Count: 3, Colour: "blue"}, {Count: 4, Colour: "red"}}},
+				{Cubes: []testCube{{Count: 1, Colour: "red"}, {Count: 2, Colour: "green"}}},
+			}},
+		},
+		{
+			"Game 2: 7 blue",
+			&testGame{ID: 2, Rounds: []testRound{{Cubes: []testCube{{Count: 7, Colour: "blue"}}}}},
+		},
+		{
+			"Card 1: 41 48 83 | 83 86 6 31",
+			&testCard{ID: 1, Winning: []int{41, 48, 83}, Picks: []uint8{83, 86, 6, 31}},
+		},
+		{"32T3K 765", &testHand{Cards: "32T3K", Bid: 765}},
+		{"KK677 -28", &testHand{Cards: "KK677", Bid: -28}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.line, func(t *testing.T) {
+			got, err := Marshal(tc.v)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if got != tc.line {
+				t.Errorf("Marshal = %q, want %q", got, tc.line)
+			}
+
+			v := reflect.New(reflect.TypeOf(tc.v).Elem())
+			if err := Unmarshal(tc.line+"\n", v.Interface()); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if !reflect.DeepEqual(v.Interface(), tc.v) {
+				t.Errorf("Unmarshal = %+v, want %+v", v.Interface(), tc.v)
+			}
+		})
+	}
+}
+
+func TestUnmarshalSpacing(t *testing.T) {
+	var g testGame
+	if err := Unmarshal("Game  3:  1 red ,2   blue;4 green", &g); err != nil {
+		t.Fatal(err)
+	}
+	want := testGame{ID: 3, Rounds: []testRound{
+		{Cubes: []testCube{{Count: 1, Colour: "red"}, {Count: 2, Colour: "blue"}}},
+		{Cubes: []testCube{{Count: 4, Colour: "green"}}},
+	}}
+	if !reflect.DeepEqual(g, want) {
+		t.Errorf("got %+v, want %+v", g, want)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		line string
+		v    any
+		want string
+	}{
+		{"Game 1: 4 green 5", &testGame{}, `line 1:17: "5": rounds[0].cubes[0].colour: unexpected text after "green"`},
+		{"Game 1: 4 green; x red", &testGame{}, `line 1:18: "x": rounds[1].cubes[0].count: invalid number`},
+		{"Game x: 4 green", &testGame{}, `line 1:6: "x": id: invalid number`},
+		{"Gme 1: 4 green", &testGame{}, `line 1:1: "Gme 1: 4 green": does not match "Game {id}: {rounds;sep=\";\"}"`},
+		{"Card 1: 1 2 | 3 300", &testCard{}, `line 1:17: "300": picks[1]: number out of range`},
+		{"32T3K", &testHand{}, `line 1:1: "32T3K": does not match "{cards} {bid}"`},
+		// Columns count runes, not bytes.
+		{"Game 1: 4 grün; x red", &testGame{}, `line 1:17: "x": rounds[1].cubes[0].count: invalid number`},
+		{"Card 1: 1 ü | 3 300", &testCard{}, `line 1:11: "ü": winning[1]: invalid number`},
+	}
+	for _, tc := range tests {
+		err := UnmarshalAt(Pos{Line: 1}, tc.line, tc.v)
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("Unmarshal(%q) = %v, want a ParseError", tc.line, err)
+			continue
+		}
+		if got := err.Error(); got != tc.want {
+			t.Errorf("Unmarshal(%q) = %s\nwant %s", tc.line, got, tc.want)
+		}
+	}
+}
+
+func TestUnmarshalBadLayout(t *testing.T) {
+	var noLayout struct{ A int }
+	if err := Unmarshal("1", &noLayout); err == nil {
+		t.Error("struct without layout: no error")
+	}
+	var notPointer testHand
+	if err := Unmarshal("32T3K 765", notPointer); err == nil {
+		t.Error("non-pointer: no error")
+	}
+}
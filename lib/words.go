@@ -0,0 +1,198 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Vocabulary maps words to the numbers they stand for.
+type Vocabulary map[string]int
+
+var (
+	// DigitVocabulary holds the digits 0-9 themselves.
+	DigitVocabulary = Vocabulary{
+		"0": 0, "1": 1, "2": 2, "3": 3, "4": 4, "5": 5, "6": 6, "7": 7, "8": 8, "9": 9,
+	}
+
+	// Vocabularies are the built-in sets of spelled-out numbers, by name.
+	Vocabularies = map[string]Vocabulary{
+		"en": {
+			"one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+			"six": 6, "seven": 7, "eight": 8, "nine": 9,
+		},
+		"zero": {"zero": 0},
+		"ordinal": {
+			"first": 1, "second": 2, "third": 3, "fourth": 4, "fifth": 5,
+			"sixth": 6, "seventh": 7, "eighth": 8, "ninth": 9,
+		},
+		"de": {
+			"null": 0, "eins": 1, "zwei": 2, "drei": 3, "vier": 4, "fünf": 5,
+			"sechs": 6, "sieben": 7, "acht": 8, "neun": 9,
+		},
+		"fr": {
+			"zéro": 0, "un": 1, "deux": 2, "trois": 3, "quatre": 4, "cinq": 5,
+			"six": 6, "sept": 7, "huit": 8, "neuf": 9,
+		},
+		"es": {
+			"cero": 0, "uno": 1, "dos": 2, "tres": 3, "cuatro": 4, "cinco": 5,
+			"seis": 6, "siete": 7, "ocho": 8, "nueve": 9,
+		},
+	}
+)
+
+// Merge returns a vocabulary holding the words of all of vs. Later
+// vocabularies win where a word is in several.
+func Merge(vs ...Vocabulary) Vocabulary {
+	res := make(Vocabulary)
+	for _, v := range vs {
+		for w, n := range v {
+			res[w] = n
+		}
+	}
+	return res
+}
+
+// ReadVocabulary reads a vocabulary of "<word> <number>" lines. Blank lines
+// and lines starting with '#' are skipped.
+func ReadVocabulary(r io.ReadCloser) (Vocabulary, error) {
+	lines, err := GetInputAll(r)
+	if err != nil {
+		return nil, err
+	}
+	res := make(Vocabulary)
+	for ln, line := range lines {
+		pos := Pos{Line: ln + 1}
+		fields := Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0].Text, "#") {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, pos.Errorf(strings.TrimSpace(line), "expected \"<word> <number>\"")
+		}
+		n, err := ParseInt(pos.Offset(fields[1].Col-1), fields[1].Text)
+		if err != nil {
+			return nil, err
+		}
+		res[fields[0].Text] = n
+	}
+	return res, nil
+}
+
+// WordMatch is a word found in a line.
+type WordMatch struct {
+	Word  string
+	Value int
+	Bytes Span // byte offsets within the line
+}
+
+func (m WordMatch) String() string {
+	return fmt.Sprintf("[%d,%d)%s=%d", m.Bytes.Start, m.Bytes.End, m.Word, m.Value)
+}
+
+// WordMatcher finds every word of a vocabulary in a line in a single pass,
+// overlapping words included, using the Aho-Corasick algorithm.
+type WordMatcher struct {
+	nodes []acNode
+	words []string
+	vocab Vocabulary
+}
+
+// acNode is a state of the automaton: the longest prefix of a word matched so
+// far.
+type acNode struct {
+	next  map[byte]int
+	fail  int // state for the longest proper suffix that is also a prefix
+	word  int // index of the word ending here, or -1
+	out   int // nearest state along the fail links where a word ends, or -1
+	depth int
+}
+
+// NewWordMatcher builds a matcher for the words of vocab.
+func NewWordMatcher(vocab Vocabulary) (*WordMatcher, error) {
+	m := &WordMatcher{vocab: vocab}
+	m.nodes = append(m.nodes, acNode{next: make(map[byte]int), word: -1, out: -1})
+
+	// Build the trie.
+	for w := range vocab {
+		if w == "" {
+			return nil, errors.New("empty word in vocabulary")
+		}
+		m.words = append(m.words, w)
+	}
+	sort.Strings(m.words)
+	for i, w := range m.words {
+		cur := 0
+		for j := 0; j < len(w); j++ {
+			nxt, ok := m.nodes[cur].next[w[j]]
+			if !ok {
+				nxt = len(m.nodes)
+				m.nodes = append(m.nodes, acNode{next: make(map[byte]int), word: -1, out: -1, depth: j + 1})
+				m.nodes[cur].next[w[j]] = nxt
+			}
+			cur = nxt
+		}
+		m.nodes[cur].word = i
+	}
+
+	// Link each state to its longest proper suffix, breadth first so that
+	// shorter states are linked before longer ones.
+	queue := []int{0}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for b, child := range m.nodes[cur].next {
+			queue = append(queue, child)
+			if cur != 0 {
+				m.nodes[child].fail = m.step(m.nodes[cur].fail, b)
+			}
+			fail := m.nodes[child].fail
+			if m.nodes[fail].word >= 0 {
+				m.nodes[child].out = fail
+			} else {
+				m.nodes[child].out = m.nodes[fail].out
+			}
+		}
+	}
+	return m, nil
+}
+
+// step returns the state after reading b in state cur.
+func (m *WordMatcher) step(cur int, b byte) int {
+	for {
+		if nxt, ok := m.nodes[cur].next[b]; ok {
+			return nxt
+		}
+		if cur == 0 {
+			return 0
+		}
+		cur = m.nodes[cur].fail
+	}
+}
+
+// FindAll returns every occurrence of a word in line, ordered by where they
+// start, shortest first.
+func (m *WordMatcher) FindAll(line string) []WordMatch {
+	var res []WordMatch
+	cur := 0
+	for i := 0; i < len(line); i++ {
+		cur = m.step(cur, line[i])
+		for s := cur; s >= 0; s = m.nodes[s].out {
+			n := m.nodes[s]
+			if n.word < 0 {
+				continue
+			}
+			w := m.words[n.word]
+			res = append(res, WordMatch{w, m.vocab[w], Span{i + 1 - n.depth, i + 1}})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Bytes.Start != res[j].Bytes.Start {
+			return res[i].Bytes.Start < res[j].Bytes.Start
+		}
+		return res[i].Bytes.End < res[j].Bytes.End
+	})
+	return res
+}
@@ -0,0 +1,114 @@
+package lib
+
+import (
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// findAllNaive is FindAll by checking every word at every offset.
+func findAllNaive(vocab Vocabulary, line string) []WordMatch {
+	var res []WordMatch
+	for i := range []byte(line) {
+		for w, n := range vocab {
+			if strings.HasPrefix(line[i:], w) {
+				res = append(res, WordMatch{w, n, Span{i, i + len(w)}})
+			}
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Bytes.Start != res[j].Bytes.Start {
+			return res[i].Bytes.Start < res[j].Bytes.Start
+		}
+		return res[i].Bytes.End < res[j].Bytes.End
+	})
+	return res
+}
+
+func matchString(ms []WordMatch) string {
+	var parts []string
+	for _, m := range ms {
+		parts = append(parts, m.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestWordMatcher(t *testing.T) {
+	tests := []struct {
+		vocab Vocabulary
+		line  string
+		want  string
+	}{
+		{Merge(DigitVocabulary, Vocabularies["en"]), "two1nine", "[0,3)two=2 [3,4)1=1 [4,8)nine=9"},
+		{Merge(DigitVocabulary, Vocabularies["en"]), "eightwothree", "[0,5)eight=8 [4,7)two=2 [7,12)three=3"},
+		{Merge(DigitVocabulary, Vocabularies["en"]), "oneight", "[0,3)one=1 [2,7)eight=8"},
+		{Merge(DigitVocabulary, Vocabularies["en"]), "xyz", ""},
+		{Merge(Vocabularies["en"], Vocabularies["ordinal"]), "seventhree", "[0,5)seven=7 [0,7)seventh=7 [5,10)three=3"},
+		{Vocabularies["de"], "fünfeins", "[0,5)fünf=5 [5,9)eins=1"},
+		// Words that are suffixes and prefixes of each other.
+		{Vocabulary{"a": 1, "aa": 2, "aaa": 3}, "aaaa", "[0,1)a=1 [0,2)aa=2 [0,3)aaa=3 [1,2)a=1 [1,3)aa=2 [1,4)aaa=3 [2,3)a=1 [2,4)aa=2 [3,4)a=1"},
+		{Vocabulary{"he": 1, "she": 2, "his": 3, "hers": 4}, "ushers", "[1,4)she=2 [2,4)he=1 [2,6)hers=4"},
+	}
+	for _, tc := range tests {
+		m, err := NewWordMatcher(tc.vocab)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := matchString(m.FindAll(tc.line)); got != tc.want {
+			t.Errorf("FindAll(%q) = %s, want %s", tc.line, got, tc.want)
+		}
+	}
+
+	if _, err := NewWordMatcher(Vocabulary{"": 0}); err == nil {
+		t.Error("empty word: no error")
+	}
+}
+
+// TestWordMatcherRandom checks FindAll against a naive search, over a small
+// alphabet so that words overlap often.
+func TestWordMatcherRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	word := func(n int) string {
+		b := make([]byte, 1+rng.Intn(n))
+		for i := range b {
+			b[i] = "abc"[rng.Intn(3)]
+		}
+		return string(b)
+	}
+	for i := 0; i < 200; i++ {
+		vocab := make(Vocabulary)
+		for j := rng.Intn(8); j >= 0; j-- {
+			vocab[word(4)] = j
+		}
+		m, err := NewWordMatcher(vocab)
+		if err != nil {
+			t.Fatal(err)
+		}
+		line := word(30)
+		if got, want := matchString(m.FindAll(line)), matchString(findAllNaive(vocab, line)); got != want {
+			t.Errorf("vocabulary %v, FindAll(%q) = %s, want %s", vocab, line, got, want)
+		}
+	}
+}
+
+func TestReadVocabulary(t *testing.T) {
+	v, err := ReadVocabulary(io.NopCloser(strings.NewReader("# Dutch\neen 1\n\n  twee\t2\nnul 0\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v) != 3 || v["een"] != 1 || v["twee"] != 2 || v["nul"] != 0 {
+		t.Errorf("ReadVocabulary = %v", v)
+	}
+
+	for _, tc := range []struct{ src, want string }{
+		{"een 1\ntwee\n", `line 2: "twee": expected "<word> <number>"`},
+		{"een x\n", `line 1:5: "x": invalid number`},
+	} {
+		_, err := ReadVocabulary(io.NopCloser(strings.NewReader(tc.src)))
+		if err == nil || err.Error() != tc.want {
+			t.Errorf("ReadVocabulary(%q) = %v, want %s", tc.src, err, tc.want)
+		}
+	}
+}